@@ -2,26 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
-	"math/rand"
+	"mime"
 	"net"
+	"net/http"
 	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"src/grpc/filetransfer" // Import the generated package
 
 	pb "src/grpc/master"
 
-	dk "src/grpc/datakeeper"
+	"src/dfsclient"
+	"src/masters"
 
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 )
 
-const PARALLEL_DOWNLOAD = false
+const invalidPathMessage = "Invalid path. Paths start with / and each part can contain only letters, numbers, dots, underscores, and hyphens."
 
 func getUserChoice() string {
 	var text string
@@ -29,89 +37,79 @@ func getUserChoice() string {
 		// Read input from user
 		fmt.Println("For Uploading a file, please enter: 1")
 		fmt.Println("For Downloading a file, please enter: 2")
+		fmt.Println("For Showing file information, please enter: 3")
+		fmt.Println("For Renaming a file, please enter: 4")
+		fmt.Println("For Deleting a file, please enter: 5")
+		fmt.Println("For Making a directory, please enter: 6")
+		fmt.Println("For Removing an empty directory, please enter: 7")
+		fmt.Println("For Listing a directory, please enter: 8")
+		fmt.Println("For Setting the replication factor of a file or directory, please enter: 9")
+		fmt.Println("For Decommissioning a data keeper or checking its progress, please enter: 10")
+		fmt.Println("For Rebalancing the data keepers, please enter: 11")
+		fmt.Println("For Showing the state of the data keepers, please enter: 12")
 		fmt.Print("Your choice: ")
 		fmt.Scanln(&text)
 
 		// Trim any leading or trailing whitespace
 		text = strings.TrimSpace(text)
 
-		// Check if the user wants to upload a file or download a file
-		if text == "1" || text == "2" {
+		// Check if the user picked one of the operations
+		if (text >= "1" && text <= "9" && len(text) == 1) || text == "10" || text == "11" || text == "12" {
 			return text // Return the user's choice
 		}
 
-		fmt.Println("Invalid input. Please enter either '1' or '2'.")
+		fmt.Println("Invalid input. Please enter a number from 1 to 12.")
 	}
 }
 
-// function upload file to server
-func uploadFile(filePath string, dataKeeperPort string) {
-	conn, err := net.Dial("tcp", dataKeeperPort)
-	if err != nil {
-		fmt.Println("Error connecting:", err.Error())
-		return
+// detectContentType guesses the MIME type of an upload from the extension of the saving path or
+// of the local file, and from the first bytes of the file when neither is known
+func detectContentType(localPath string, savingPath string) string {
+	for _, ext := range []string{path.Ext(savingPath), filepath.Ext(localPath)} {
+		if contentType := mime.TypeByExtension(ext); contentType != "" {
+			return contentType
+		}
 	}
-	defer conn.Close()
-
-	// Open the .mp4 file to be sent
-	file, err := os.Open(filePath)
+	file, err := os.Open(localPath)
 	if err != nil {
-		fmt.Println("Error opening file:", err.Error())
-		return
+		return ""
 	}
 	defer file.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	return http.DetectContentType(head[:n])
+}
 
-	// Send file data to server
-	_, err = io.Copy(conn, file)
-	if err != nil {
-		fmt.Println("Error sending file:", err.Error())
-		return
+// sectionChecksum returns the hex SHA-256 of size bytes of file starting at offset
+func sectionChecksum(file *os.File, offset int64, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, offset, size)); err != nil {
+		return "", err
 	}
-	fmt.Println("File sent successfully!")
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// function handle connection
-func handleConnection(conn net.Conn, fileName string) {
-	defer conn.Close()
-
-	// Create a new file to save the received .mp4 file
-	file, err := os.Create("client/" + fileName + ".mp4")
+// uploadFile sends a file through the upload session of the master. Blocks are staged on their data keepers
+// and committed one by one, and a block whose transfer broke resumes from what its data keeper already holds.
+func uploadFile(files *dfsclient.Client, filePath string, fileName string, opts dfsclient.CreateOptions) {
+	// Open the file to be sent
+	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Println("Error creating file:", err.Error())
+		fmt.Println("Error opening file:", err.Error())
 		return
 	}
 	defer file.Close()
-
-	// Receive .mp4 file data from client and save it to the file
-	_, err = io.Copy(file, conn)
-	if err != nil {
-		fmt.Println("Error receiving file:", err.Error())
-		return
-	}
-
-	fmt.Println("File received and saved:", fileName + ".mp4")
-}
-
-// function download file from server
-func downloadFile(port string, fileName string) {
-	listener, err := net.Listen("tcp", port)
+	info, err := file.Stat()
 	if err != nil {
-		fmt.Println("Error listening:", err.Error())
+		fmt.Println("Error opening file:", err.Error())
 		return
 	}
-	defer listener.Close()
-	fmt.Println("TCP Server started. Listening on port", port)
 
-	// Accept incoming connections
-	conn, err := listener.Accept()
-	if err != nil {
-		fmt.Println("Error accepting connection:", err.Error())
+	if err := files.Upload(context.Background(), fileName, file, info.Size(), opts); err != nil {
+		fmt.Println("Error sending file:", err)
 		return
 	}
-	fmt.Println("Client connected:", conn.RemoteAddr())
-
-	// Handle incoming connection in a separate goroutine
-	go handleConnection(conn, fileName)
+	fmt.Println("File sent successfully!")
 }
 
 type portNumberServer struct {
@@ -157,171 +155,184 @@ func myServer(grpcAddress string) {
 	}
 }
 
-func isValidFileName(fileName string) bool {
-	// Regular expression to match file name
-	regex := "^[a-zA-Z0-9_-]+$"
-	match, _ := regexp.MatchString(regex, fileName)
+// isValidPath checks an absolute path like /projects/demo/clip
+func isValidPath(filePath string) bool {
+	// Every path component can contain only letters, numbers, dots, underscores and hyphens
+	regex := "^(/[a-zA-Z0-9_.-]+)+$"
+	match, _ := regexp.MatchString(regex, filePath)
 	return match
 }
 
-func download(ports []string, fileName string, myPortNumber string) {
-	dataKeeperPort := ports[rand.Intn(len(ports))]
-	fmt.Println("Download from Data keeper port:", dataKeeperPort)
-
-	conn, err := grpc.Dial(dataKeeperPort, grpc.WithInsecure())
+// download fetches a file through the client package into client/ under the file's original name
+// and checks the result against the checksum the file was uploaded with
+func download(files *dfsclient.Client, fileName string, fileSize int64, checksum string) {
+	// Create a new file to save the received file
+	filePath := "client/" + path.Base(fileName)
+	file, err := os.Create(filePath)
 	if err != nil {
-		fmt.Println("did not connect:", err)
+		fmt.Println("Error creating file:", err.Error())
 		return
 	}
-	defer conn.Close()
-	d := dk.NewDataKeeperServiceClient(conn)
-	resp3, err3 := d.TransferFile(context.Background(), &dk.FilePortRequest{Filename: fileName, PortNumber: myPortNumber})
-	if err3 != nil {
-		fmt.Println("Error calling DownloadFile:", err3)
+	defer file.Close()
+	if err := file.Truncate(fileSize); err != nil {
+		fmt.Println("Error creating file:", err.Error())
 		return
 	}
-	successMsg := resp3.GetSuccess()
-	if successMsg {
-	fmt.Println("File downloaded successfully!")
-	} else {
-		fmt.Println("Error downloading file")
-	}
-}
 
-func downloadChunk(dataKeeperPort string, fileName string, startByte int64, endByte int64) ([]byte, error) {
-	conn, err := grpc.Dial(dataKeeperPort, grpc.WithInsecure())
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to data keeper: %v", err)
+	// Blocks are split into ranges fetched in parallel, unless PARALLEL_DOWNLOAD=false
+	if err := files.Download(context.Background(), fileName, file); err != nil {
+		fmt.Println("Error downloading file:", err)
+		os.Remove(filePath)
+		return
 	}
-	defer conn.Close()
-
-	c := dk.NewDataKeeperServiceClient(conn)
-	resp, err := c.DownloadChunk(context.Background(), &dk.DownloadChunkRequest{
-		FileName:  fileName,
-		StartByte: startByte,
-		EndByte:   endByte,
-	})
 
+	received, err := sectionChecksum(file, 0, fileSize)
 	if err != nil {
-		return nil, fmt.Errorf("error downloading chunk from data keeper: %v", err)
+		fmt.Println("Error verifying file:", err)
+		return
 	}
-
-	return resp.GetChunk(), nil
+	if checksum != "" && received != checksum {
+		fmt.Printf("Error downloading file: checksum %s, expected %s\n", received, checksum)
+		os.Remove(filePath)
+		return
+	}
+	fmt.Println("File received, verified and saved:", path.Base(fileName))
 }
 
-func downloadFileParallel(fileName string, fileSize int64, ports []string, numChunks int) ([]byte, error) {
-	chunkSize := fileSize / int64(numChunks)
-
-	// Create channels to collect chunks from different goroutines
-	chunkChannels := make([]chan []byte, numChunks)
-	for i := range chunkChannels {
-		chunkChannels[i] = make(chan []byte)
+// readReplication asks for a replication factor. An empty or invalid answer means 0, which follows the directory.
+func readReplication() int32 {
+	fmt.Print("Enter the replication factor (0 or empty to follow the directory): ")
+	var text string
+	fmt.Scanln(&text)
+	replication, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || replication < 0 {
+		return 0
 	}
+	return int32(replication)
+}
 
-	// Download chunks from different data keepers concurrently
-	for i := 0; i < numChunks; i++ {
-		startByte := int64(i) * chunkSize
-		endByte := startByte + chunkSize - 1
-		if i == numChunks-1 {
-			endByte = fileSize - 1 // Ensure last chunk reaches EOF
-		}
+// listPageSize is the number of entries fetched per ListFiles call
+const listPageSize = 50
 
-		go func(index int, start, end int64) {
-			dataKeeperPort := ports[index%len(ports)] // Round-robin selection of data keeper
-			chunk, err := downloadChunk(dataKeeperPort, fileName, start, end)
-			if err != nil {
-				fmt.Printf("Error downloading chunk %d: %v\n", index, err)
-				chunkChannels[index] <- nil
-				return
+// listFiles prints every entry of a directory, page by page
+func listFiles(c pb.MasterTrackerServiceClient, dirPath string, recursive bool) {
+	pageToken := ""
+	count := 0
+	for {
+		resp, err := c.ListFiles(context.Background(), &pb.ListFilesRequest{Path: dirPath, Recursive: recursive, PageSize: listPageSize, PageToken: pageToken})
+		if err != nil {
+			fmt.Println("Error calling ListFiles:", err)
+			return
+		}
+		for _, entry := range resp.GetEntries() {
+			modified := time.Unix(entry.GetModifiedAt(), 0).Format(time.RFC3339)
+			if entry.GetIsDir() {
+				fmt.Printf("%-10s %s %s/\n", "<dir>", modified, entry.GetPath())
+			} else {
+				fmt.Printf("%-10d %s %s\n", entry.GetSize(), modified, entry.GetPath())
 			}
-			chunkChannels[index] <- chunk
-		}(i, startByte, endByte)
+			count++
+		}
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
 	}
+	fmt.Printf("%d entries\n", count)
+}
 
-	// Collect all chunks from channels
-	var fileContent []byte
-	for _, ch := range chunkChannels {
-		chunk := <-ch
-		if chunk == nil {
-			return nil, fmt.Errorf("error downloading one or more chunks")
+// nodeHistoryLimit is the number of state changes shown
+const nodeHistoryLimit = 20
+
+// printNodeHistory shows the state of every data keeper and its latest state changes
+func printNodeHistory(history *pb.NodeHistoryResponse) {
+	for _, node := range history.GetNodes() {
+		state := node.GetState()
+		if node.GetDecommissioning() {
+			state += ", decommissioning"
 		}
-		fileContent = append(fileContent, chunk...)
+		fmt.Printf("Data keeper %d: %s, last heartbeat %d ms ago, phi %.1f, %d of %d bytes used, %d transfers\n",
+			node.GetDataNodeId(), state, node.GetLastHeartbeat(), node.GetPhi(), node.GetUsed(), node.GetCapacity(), node.GetInFlight())
+	}
+	for _, change := range history.GetChanges() {
+		fmt.Printf("%s  data keeper %d: %s -> %s (%s)\n", time.Unix(change.GetTime(), 0).Format(time.DateTime),
+			change.GetDataNodeId(), change.GetFrom(), change.GetTo(), change.GetReason())
 	}
-
-	return fileContent, nil
 }
 
-func parallelDownload(ports []string, fileName string, fileSize int64) {
-	numChunks := len(ports) // Number of data keepers
-	fileContent, err := downloadFileParallel(fileName, fileSize, ports, numChunks)
-	if err != nil {
-		fmt.Println("Error downloading file:", err)
+// printDecommission shows how far the decommissioning of a data keeper got
+func printDecommission(progress *pb.DecommissionNodeResponse) {
+	if !progress.GetDecommissioning() {
+		fmt.Printf("Data keeper %d is in service\n", progress.GetDataNodeId())
 		return
 	}
+	fmt.Printf("Data keeper %d is being decommissioned: %d of %d blocks still need copies\n",
+		progress.GetDataNodeId(), progress.GetRemainingBlocks(), progress.GetTotalBlocks())
+	if progress.GetSafeToRemove() {
+		fmt.Println("It can be removed safely")
+	}
+}
 
-	// Save the downloaded file
-	err = os.WriteFile("client/" + fileName + ".mp4", fileContent, 0644)
-	if err != nil {
-		fmt.Println("Error saving file:", err)
-		return
+// printStat shows the size, checksum, timestamps and replica locations of a file
+func printStat(stat *pb.StatFileResponse) {
+	fmt.Println("File name:", stat.GetFileName())
+	fmt.Println("File size:", stat.GetFileSize())
+	fmt.Println("Content type:", stat.GetContentType())
+	fmt.Println("Replication factor:", stat.GetReplication())
+	fmt.Println("Checksum:", stat.GetChecksum())
+	fmt.Println("Created:", time.Unix(stat.GetCreatedAt(), 0).Format(time.RFC3339))
+	fmt.Println("Modified:", time.Unix(stat.GetModifiedAt(), 0).Format(time.RFC3339))
+	for _, block := range stat.GetBlocks() {
+		fmt.Printf("Block %s (%d bytes at offset %d)\n", block.GetBlockId(), block.GetSize(), block.GetOffset())
+		for _, replica := range block.GetReplicas() {
+			state := "alive"
+			if !replica.GetAlive() {
+				state = "dead"
+			}
+			fmt.Printf("  Data Keeper %d at %s (%s)\n", replica.GetDataNodeId(), replica.GetGrpcAddress(), state)
+		}
 	}
-	fmt.Println("File downloaded successfully!")	
 }
 
 func main() {
 	// read port and grpc port from terminal args
-	if len(os.Args) != 3 {
-		fmt.Println("Usage: go run client.go <port> <grpc_port>")
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: go run ./client <grpc_port>")
 		return
 	}
 
-	myPortNumber := os.Args[1]
-	grpcAddress := os.Args[2]
+	grpcAddress := os.Args[1]
 
 	// Load the environment variables from the .env file
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
-	masterPort := os.Getenv("MASTER_PORT")
 
 	// Start the gRPC server in a separate goroutine
 	go myServer(grpcAddress)
 
 
-	conn, err := grpc.Dial(masterPort, grpc.WithInsecure())
-	if err != nil {
-		fmt.Println("did not connect:", err)
-		return
-	}
+	// Calls are sent to whichever master currently leads the group
+	conn := masters.Dial(masters.Addresses())
 	defer conn.Close()
 	c := pb.NewMasterTrackerServiceClient(conn)
+	files := dfsclient.Dial(masters.Addresses())
+	defer files.Close()
 	
 	for {
 		// Read input from user
 		userChoice := getUserChoice()
 
-		resp := &pb.UploadFileResponse{}
 		err = nil
 		if userChoice == "1" {
-			resp, err = c.UploadFile(context.Background(), &pb.UploadFileRequest{
-				ClientPort: grpcAddress,
-			})
-			if err != nil {
-				fmt.Println("Error calling UploadFile:", err)
-				continue
-			}
-			dataKeeperPort := resp.GetUploadAddress()
-			dataKeeperPortGrpc := resp.GetGrpcAddress()
-			fmt.Println("Your data keeper port number:", dataKeeperPort)
-
 			// Ask the user for the file path
 			fmt.Print("Enter the file path: ")
 			var filePath string
 			fmt.Scanln(&filePath)
 
-			// Ask the user for the saving filename
-			fmt.Print("Enter the saving filename: ")
+			// Ask the user for the saving path
+			fmt.Print("Enter the saving path (e.g. /projects/demo/clip): ")
 			var fileName string
 			fmt.Scanln(&fileName)
 
@@ -332,40 +343,24 @@ func main() {
 				continue
 			}
 
-			// Validate file name (Can contain only letters, numbers, underscores, and hyphens)
-			if !isValidFileName(fileName) {
-				fmt.Println("Invalid file name. File name can contain only letters, numbers, underscores, and hyphens.")
+			// Validate the path (Components can contain only letters, numbers, underscores, and hyphens)
+			if !isValidPath(fileName) {
+				fmt.Println(invalidPathMessage)
 				continue
 			}
 
-			// Connect to the data keeper
-			conn1,err1 := grpc.Dial(dataKeeperPortGrpc, grpc.WithInsecure())
-			if err1 != nil {
-				fmt.Println("did not connect:", err1)
-				continue
-			}
-			d := dk.NewFileSaveServiceClient(conn1)
-			// for sending filename
-			resp1, err1 := d.SaveFile(context.Background(), &dk.FileSaveRequest{Filename: fileName})
-			if err1 != nil {
-				fmt.Println("Error calling SaveFile:", err1)
-				continue
-			}
-			successMsg := resp1.GetSuccess()
-			if !successMsg {
-				fmt.Println("Error sending file name ")
-				continue
-			}
-			uploadFile(filePath, dataKeeperPort)
-			//////////////////////////////////////
-			conn1.Close()
-		} else {
-			fmt.Print("Enter the file name: ")
+			uploadFile(files, filePath, fileName, dfsclient.CreateOptions{
+				ClientPort: grpcAddress,
+				ContentType: detectContentType(filePath, fileName),
+				Replication: readReplication(),
+			})
+		} else if userChoice == "2" {
+			fmt.Print("Enter the file path: ")
 			var fileName string
 			fmt.Scanln(&fileName)
 
-			if !isValidFileName(fileName) {
-				fmt.Println("Invalid file name. File name can contain only letters, numbers, underscores, and hyphens.")
+			if !isValidPath(fileName) {
+				fmt.Println(invalidPathMessage)
 				continue
 			}
 
@@ -377,19 +372,174 @@ func main() {
 				fmt.Println("Error calling DownloadFile:", err)
 				continue
 			}
-			ports := resp2.GetAddresses()
+			blocks := resp2.GetBlocks()
 			fileSize := resp2.GetFileSize()
-			if len(ports) == 0 {
+			if len(blocks) == 0 {
 				fmt.Println("Incorrect fileName or there are no aviailable data keepers.")
 				continue
 			}
-			fmt.Println("Number of data keepers:", len(ports))
+			unavailable := false
+			for _, block := range blocks {
+				if len(block.GetAddresses()) == 0 {
+					unavailable = true
+				}
+			}
+			if unavailable {
+				fmt.Println("Some blocks of the file have no available data keepers.")
+				continue
+			}
+			fmt.Println("Number of blocks:", len(blocks))
 			fmt.Println("File size:", fileSize)
-			if PARALLEL_DOWNLOAD {
-				parallelDownload(ports, fileName, fileSize)
+			fmt.Println("Content type:", resp2.GetContentType())
+			download(files, resp2.GetFileName(), fileSize, resp2.GetChecksum())
+		} else if userChoice == "9" {
+			fmt.Print("Enter the file or directory path: ")
+			var target string
+			fmt.Scanln(&target)
+
+			if target != "/" && !isValidPath(target) {
+				fmt.Println(invalidPathMessage)
+				continue
+			}
+			replication := readReplication()
+			if _, err := c.SetReplication(context.Background(), &pb.SetReplicationRequest{Path: target, Replication: replication}); err != nil {
+				fmt.Println("Error calling SetReplication:", err)
+				continue
+			}
+			fmt.Printf("Replication factor of %s set to %d\n", target, replication)
+		} else if userChoice == "10" {
+			fmt.Print("Enter the data keeper id: ")
+			var idText string
+			fmt.Scanln(&idText)
+			dataNodeId, err := strconv.Atoi(idText)
+			if err != nil {
+				fmt.Println("Invalid id:", err)
+				continue
+			}
+			fmt.Print("Put the data keeper back in service instead? (y/N): ")
+			var answer string
+			fmt.Scanln(&answer)
+
+			progress, err := c.DecommissionNode(context.Background(), &pb.DecommissionNodeRequest{DataNodeId: int32(dataNodeId), Cancel: strings.EqualFold(answer, "y")})
+			if err != nil {
+				fmt.Println("Error calling DecommissionNode:", err)
+				continue
+			}
+			printDecommission(progress)
+		} else if userChoice == "11" {
+			fmt.Print("Enter the threshold in percent (empty for the default): ")
+			var thresholdText string
+			fmt.Scanln(&thresholdText)
+			var threshold float64
+			if thresholdText != "" {
+				threshold, err = strconv.ParseFloat(thresholdText, 64)
+				if err != nil {
+					fmt.Println("Invalid threshold:", err)
+					continue
+				}
+			}
+			fmt.Print("Only show the planned moves? (y/N): ")
+			var answer string
+			fmt.Scanln(&answer)
+			dryRun := strings.EqualFold(answer, "y")
+
+			resp, err := c.Rebalance(context.Background(), &pb.RebalanceRequest{Threshold: threshold, DryRun: dryRun})
+			if err != nil {
+				fmt.Println("Error calling Rebalance:", err)
+				continue
+			}
+			fmt.Printf("Average utilisation: %.1f%%\n", resp.GetAverageUtilization())
+			for _, move := range resp.GetMoves() {
+				fmt.Printf("Block %s (%d bytes): data keeper %d -> %d\n", move.GetBlockId(), move.GetSize(), move.GetSource(), move.GetDestination())
+			}
+			if dryRun {
+				fmt.Printf("%d replicas would be moved\n", len(resp.GetMoves()))
+			} else {
+				fmt.Printf("Moving %d replicas\n", len(resp.GetMoves()))
+			}
+		} else if userChoice == "12" {
+			fmt.Print("Enter the data keeper id (empty for every data keeper): ")
+			var idText string
+			fmt.Scanln(&idText)
+			var dataNodeId int
+			if idText != "" {
+				dataNodeId, err = strconv.Atoi(idText)
+				if err != nil {
+					fmt.Println("Invalid id:", err)
+					continue
+				}
+			}
+			history, err := c.NodeHistory(context.Background(), &pb.NodeHistoryRequest{DataNodeId: int32(dataNodeId), Limit: nodeHistoryLimit})
+			if err != nil {
+				fmt.Println("Error calling NodeHistory:", err)
+				continue
+			}
+			printNodeHistory(history)
+		} else if userChoice <= "5" {
+			fmt.Print("Enter the file path: ")
+			var fileName string
+			fmt.Scanln(&fileName)
+
+			if !isValidPath(fileName) {
+				fmt.Println(invalidPathMessage)
+				continue
+			}
+
+			if userChoice == "3" {
+				stat, err := c.StatFile(context.Background(), &pb.StatFileRequest{FileName: fileName})
+				if err != nil {
+					fmt.Println("Error calling StatFile:", err)
+					continue
+				}
+				printStat(stat)
+			} else if userChoice == "4" {
+				fmt.Print("Enter the new file path: ")
+				var newName string
+				fmt.Scanln(&newName)
+
+				if !isValidPath(newName) {
+					fmt.Println(invalidPathMessage)
+					continue
+				}
+				if _, err := c.RenameFile(context.Background(), &pb.RenameFileRequest{FileName: fileName, NewName: newName}); err != nil {
+					fmt.Println("Error calling RenameFile:", err)
+					continue
+				}
+				fmt.Printf("File %s renamed to %s\n", fileName, newName)
+			} else {
+				if _, err := c.DeleteFile(context.Background(), &pb.DeleteFileRequest{FileName: fileName}); err != nil {
+					fmt.Println("Error calling DeleteFile:", err)
+					continue
+				}
+				fmt.Println("File deleted:", fileName)
+			}
+		} else {
+			fmt.Print("Enter the directory path: ")
+			var dirPath string
+			fmt.Scanln(&dirPath)
+
+			if dirPath != "/" && !isValidPath(dirPath) {
+				fmt.Println(invalidPathMessage)
+				continue
+			}
+
+			if userChoice == "6" {
+				if _, err := c.Mkdir(context.Background(), &pb.MkdirRequest{Path: dirPath, Parents: true}); err != nil {
+					fmt.Println("Error calling Mkdir:", err)
+					continue
+				}
+				fmt.Println("Directory created:", dirPath)
+			} else if userChoice == "7" {
+				if _, err := c.Rmdir(context.Background(), &pb.RmdirRequest{Path: dirPath}); err != nil {
+					fmt.Println("Error calling Rmdir:", err)
+					continue
+				}
+				fmt.Println("Directory removed:", dirPath)
 			} else {
-				go downloadFile(myPortNumber, fileName)
-				download(ports, fileName, myPortNumber)
+				fmt.Print("List recursively? (y/n): ")
+				var recursive string
+				fmt.Scanln(&recursive)
+				listFiles(c, dirPath, strings.TrimSpace(recursive) == "y")
 			}
 		}
 	}
@@ -0,0 +1,123 @@
+// Package connpool shares gRPC connections between callers, keyed by address. Connections are
+// checked before they are handed out and closed once they fail or sit unused for too long.
+package connpool
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Pool keeps one connection per address for as long as it is healthy and in use
+type Pool struct {
+	mu          sync.Mutex
+	conns       map[string]*entry
+	idleTimeout time.Duration
+}
+
+type entry struct {
+	conn     *grpc.ClientConn
+	inUse    int
+	lastUsed time.Time
+	retired  bool // replaced in the pool, closed when the last user releases it
+}
+
+// New creates a pool that closes connections unused for idleTimeout
+func New(idleTimeout time.Duration) *Pool {
+	p := &Pool{conns: make(map[string]*entry), idleTimeout: idleTimeout}
+	go p.maintain()
+	return p
+}
+
+var (
+	shared     *Pool
+	sharedOnce sync.Once
+)
+
+// Get takes a connection from the pool shared by the whole process. Its idle timeout is read
+// from CONN_IDLE_TIMEOUT (in seconds, 300 by default) on first use.
+func Get(address string) (*grpc.ClientConn, func(), error) {
+	sharedOnce.Do(func() {
+		seconds, err := strconv.Atoi(os.Getenv("CONN_IDLE_TIMEOUT"))
+		if err != nil || seconds <= 0 {
+			seconds = 300
+		}
+		shared = New(time.Duration(seconds) * time.Second)
+	})
+	return shared.Get(address)
+}
+
+// healthy reports whether a connection is usable or can become usable without being replaced
+func healthy(conn *grpc.ClientConn) bool {
+	state := conn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
+}
+
+// Get returns a connection to address and a function that must be called once the caller is done
+// with it. A connection that failed is replaced by a new one.
+func (p *Pool) Get(address string) (*grpc.ClientConn, func(), error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.conns[address]
+	if ok && !healthy(e.conn) {
+		p.retire(address, e)
+		ok = false
+	}
+	if !ok {
+		conn, err := grpc.Dial(address, grpc.WithInsecure())
+		if err != nil {
+			return nil, nil, err
+		}
+		e = &entry{conn: conn}
+		p.conns[address] = e
+	}
+	e.inUse++
+	e.lastUsed = time.Now()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			e.inUse--
+			e.lastUsed = time.Now()
+			if e.retired && e.inUse == 0 {
+				e.conn.Close()
+			}
+		})
+	}
+	return e.conn, release, nil
+}
+
+// retire removes an entry from the pool, closing it unless someone still uses it. It must be called with p.mu held.
+func (p *Pool) retire(address string, e *entry) {
+	delete(p.conns, address)
+	e.retired = true
+	if e.inUse == 0 {
+		e.conn.Close()
+	}
+}
+
+// maintain periodically closes the connections that failed or were not used for idleTimeout
+func (p *Pool) maintain() {
+	interval := max(p.idleTimeout/2, time.Second)
+	for {
+		time.Sleep(interval)
+		p.sweep()
+	}
+}
+
+// sweep closes the connections nobody uses that failed or sat idle for too long
+func (p *Pool) sweep() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for address, e := range p.conns {
+		if e.inUse == 0 && (time.Since(e.lastUsed) > p.idleTimeout || !healthy(e.conn)) {
+			p.retire(address, e)
+		}
+	}
+}
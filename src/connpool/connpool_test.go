@@ -0,0 +1,108 @@
+package connpool
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// newPool creates a pool without its maintenance loop, so tests sweep it themselves, and a
+// server to connect to, so connections only fail when a test closes them
+func newPool(t *testing.T) (*Pool, string) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	go server.Serve(listener)
+	p := &Pool{conns: make(map[string]*entry), idleTimeout: time.Minute}
+	t.Cleanup(func() {
+		for _, e := range p.conns {
+			e.conn.Close()
+		}
+		server.Stop()
+	})
+	return p, listener.Addr().String()
+}
+
+func TestPoolGet(t *testing.T) {
+	p, address := newPool(t)
+	a1, releaseA1, err := p.Get(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2, releaseA2, _ := p.Get(address)
+	b, releaseB, _ := p.Get("localhost:1")
+	if a1 != a2 {
+		t.Error("two connections to the same address")
+	}
+	if a1 == b {
+		t.Error("one connection shared by two addresses")
+	}
+
+	// Releasing twice counts once
+	releaseA2()
+	releaseA2()
+	if inUse := p.conns[address].inUse; inUse != 1 {
+		t.Errorf("connection used %d times, want 1", inUse)
+	}
+
+	// A connection that shut down is replaced
+	b.Close()
+	b2, releaseB2, _ := p.Get("localhost:1")
+	if b2 == b {
+		t.Error("handed out a connection that shut down")
+	}
+	releaseB()
+	releaseB2()
+	releaseA1()
+}
+
+func TestPoolRetireWaitsForUsers(t *testing.T) {
+	p, address := newPool(t)
+	conn, release, _ := p.Get(address)
+	p.mu.Lock()
+	p.retire(address, p.conns[address])
+	p.mu.Unlock()
+	if conn.GetState() == connectivity.Shutdown {
+		t.Fatal("closed a connection still in use")
+	}
+	if _, ok := p.conns[address]; ok {
+		t.Error("retired connection still handed out")
+	}
+	release()
+	if conn.GetState() != connectivity.Shutdown {
+		t.Error("retired connection not closed by its last user")
+	}
+}
+
+func TestPoolSweep(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		inUse  bool
+		idle   time.Duration
+		closed bool
+	}{
+		{"recently used", false, time.Second, false},
+		{"idle too long", false, time.Hour, true},
+		{"in use for long", true, time.Hour, false},
+	} {
+		p, address := newPool(t)
+		conn, release, _ := p.Get(address)
+		if !c.inUse {
+			release()
+		}
+		p.conns[address].lastUsed = time.Now().Add(-c.idle)
+		p.sweep()
+		if closed := conn.GetState() == connectivity.Shutdown; closed != c.closed {
+			t.Errorf("%s: closed %v, want %v", c.name, closed, c.closed)
+		}
+		if _, pooled := p.conns[address]; pooled == c.closed {
+			t.Errorf("%s: still pooled %v", c.name, pooled)
+		}
+		release()
+	}
+}
@@ -2,23 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"log"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
-	"time"
+	"strings"
 
 	pb "src/grpc/datakeeper"
 
 	ms "src/grpc/master"
 
+	"src/connpool"
+	"src/masters"
+
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var fileName = "received"
 var id string
 
 // DataKeeperClient represents a gRPC client for communicating with the Data Keeper node.
@@ -42,161 +50,245 @@ func (c *DataKeeperClient) Close() {
 	c.conn.Close()
 }
 
-func waitAndPrint(id int) {
-	for {
-		time.Sleep(time.Second)
-		err2 := godotenv.Load()
-		if err2 != nil {
-			log.Fatal("Error loading .env file")
-		}
+// streamChunkSize is the size of the data carried by one Upload or Download message
+const streamChunkSize = 1 << 20
 
-		// Connecting with master
-		masterPort := os.Getenv("MASTER_PORT")
-		conn, err := grpc.Dial(masterPort, grpc.WithInsecure())
-		if err != nil {
-			fmt.Println("did not connect:", err)
-			return
-		}
-		defer conn.Close()
-		c := ms.NewMasterTrackerServiceClient(conn)
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
 
-		// Calling Heartbeat service
-		_, err = c.Heartbeat(context.Background(), &ms.HeartbeatRequest{DataNodeId: int32(id)})
-		if err != nil {
-			fmt.Println("Error calling Heartbeat:", err)
-			return
-		}
-	}
+// registerFile tells the master that the block is now stored on this data keeper
+func registerFile(blockId string, blockSize int64, checksum string, sessionId string) error {
+	// Connecting with the master group
+	conn := masters.Dial(masters.Addresses())
+	defer conn.Close()
+	c := ms.NewMasterTrackerServiceClient(conn)
+	idInt, _ := strconv.Atoi(id)
+
+	// Calling RegisterFile service
+	_, err := c.RegisterFile(context.Background(), &ms.RegisterFileRequest{
+		BlockId: blockId,
+		DataNodeId: int32(idInt),
+		FilePath: blockPath(blockId),
+		FileSize: blockSize,
+		SessionId: sessionId,
+		Checksum: checksum,
+	})
+	return err
 }
 
-func handleConnection(conn net.Conn) {
-	defer conn.Close()
+// blockPath is where a block is stored. Blocks are kept under their opaque id, whatever the
+// name and type of the file they belong to.
+func blockPath(blockId string) string {
+	return "datakeeper/" + id + "/" + blockId
+}
+
+// isValidBlockId keeps ids sent by clients and peers from escaping the storage folder or clashing with temporary files
+func isValidBlockId(blockId string) bool {
+	return blockId != "" && !strings.ContainsAny(blockId, "/\\") && !strings.HasPrefix(blockId, ".") &&
+		!strings.HasSuffix(blockId, ".part") && !strings.HasSuffix(blockId, checksumSuffix)
+}
+
+type server struct {
+	pb.UnimplementedDataKeeperServiceServer
+}
+
+// Upload receives a file from a client or from another data keeper. The bytes are written to a
+// temporary file that only takes the real name once every chunk arrived in order and intact
+// and the whole file matches the SHA-256 announced by the sender.
+func (s *server) Upload(stream pb.DataKeeperService_UploadServer) error {
+	defer trackTransfer()()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	fileName := first.GetFileName()
+	sessionId := first.GetSessionId()
+	expected := first.GetSha256()
+	if fileName == "" || expected == "" {
+		return status.Error(codes.InvalidArgument, "the first upload message must carry the file name and checksum")
+	}
+	if !isValidBlockId(fileName) {
+		return status.Errorf(codes.InvalidArgument, "invalid block id %q", fileName)
+	}
 
 	folderPath := "datakeeper/" + id + "/"
 	if _, err := os.Stat(folderPath); os.IsNotExist(err) {
 		os.Mkdir(folderPath, 0755)
 	}
-	// Create a new file to save the received .mp4 file
-	file, err := os.Create(folderPath + fileName + ".mp4")
+	file, err := os.CreateTemp(folderPath, fileName + ".*.part")
 	if err != nil {
 		fmt.Println("Error creating file:", err.Error())
-		return
+		return status.Errorf(codes.Internal, "creating file: %v", err)
 	}
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath)
 	defer file.Close()
 
-	// Receive .mp4 file data from client and save it to the file
-	_, err = io.Copy(file, conn)
-	if err != nil {
-		fmt.Println("Error receiving file:", err.Error())
-		return
-	}
+	hash := sha256.New()
+	var size int64
+	for chunk := first; ; {
+		if chunk.GetOffset() != size {
+			return status.Errorf(codes.InvalidArgument, "chunk at offset %d, expected %d", chunk.GetOffset(), size)
+		}
+		if crc32.Checksum(chunk.GetData(), crc32cTable) != chunk.GetCrc32C() {
+			return status.Errorf(codes.DataLoss, "checksum mismatch in chunk at offset %d", chunk.GetOffset())
+		}
+		if _, err := file.Write(chunk.GetData()); err != nil {
+			return status.Errorf(codes.Internal, "writing file: %v", err)
+		}
+		hash.Write(chunk.GetData())
+		size += int64(len(chunk.GetData()))
 
-	fmt.Println("File received and saved:", fileName + ".mp4")
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Println("Error receiving file:", err)
+			return err
+		}
+	}
 
-	err = godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if checksum != expected {
+		fmt.Printf("Rejected %s: checksum %s, expected %s\n", fileName, checksum, expected)
+		return status.Errorf(codes.DataLoss, "checksum mismatch for %s", fileName)
 	}
 
-	// Connecting with master
-	masterPort := os.Getenv("MASTER_PORT")
-	conn2, err2 := grpc.Dial(masterPort, grpc.WithInsecure())
-	if err2 != nil {
-		fmt.Println("did not connect:", err2)
-		return
+	if err := file.Sync(); err != nil {
+		return status.Errorf(codes.Internal, "syncing file: %v", err)
 	}
-	defer conn2.Close()
-	c := ms.NewMasterTrackerServiceClient(conn2)
-	idInt, _ := strconv.Atoi(id)
-	idInt32 := int32(idInt)
+	file.Close()
+	if err := storeBlock(tmpPath, fileName, size, checksum, sessionId); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.UploadResponse{Success: true, Size: size, Sha256: checksum})
+}
 
-	// Get the file size
-	fileInfo, err := file.Stat()
-	if err != nil {
-		fmt.Println("Error getting file info:", err)
-		return
+// storeBlock moves a received block from tmpPath into place and registers it with the master
+func storeBlock(tmpPath string, fileName string, size int64, checksum string, sessionId string) error {
+	filePath := blockPath(fileName)
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return status.Errorf(codes.Internal, "saving file: %v", err)
+	}
+	// The scrubber checks the file against this checksum later on
+	if err := writeChecksum(filePath, checksum); err != nil {
+		fmt.Println("Error saving checksum:", err)
 	}
-	fileSize := fileInfo.Size()
+	fmt.Println("File received and saved:", fileName)
 
-	// Calling RegisterFile service
-	_, err = c.RegisterFile(context.Background(), &ms.RegisterFileRequest{
-		FileName: fileName,
-		DataNodeId: idInt32,
-		FilePath: "datakeeper/" + id + "/" + fileName + ".mp4",
-		FileSize: fileSize,
-	})
-	if err != nil {
+	if err := registerFile(fileName, size, checksum, sessionId); err != nil {
 		fmt.Println("Error calling RegisterFile:", err)
-		return
+		switch status.Code(err) {
+		case codes.DataLoss:
+			// The master holds a different copy, so this one is useless
+			os.Remove(filePath)
+			return err
+		case codes.AlreadyExists, codes.NotFound, codes.Aborted:
+			// The path of the file was taken or removed during the upload, or the master lost the
+			// session, so sending the block again will not help
+			return err
+		}
+		return notRegistered(fileName, err)
 	}
+	return nil
 }
 
-func listenForDownload(port string) {
-	// Listen on the given port
-	listener, err := net.Listen("tcp", port)
+// notRegistered is the error for a block that is stored but that the master could not register.
+// Its detail tells clients to commit the block again rather than to upload it elsewhere.
+func notRegistered(fileName string, err error) error {
+	st := status.Newf(codes.Unavailable, "registering file: %v", err)
+	if detailed, detailErr := st.WithDetails(&pb.NotRegistered{FileName: fileName}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// Download streams a stored file in chunks
+func (s *server) Download(req *pb.DownloadRequest, stream pb.DataKeeperService_DownloadServer) error {
+	defer trackTransfer()()
+	file, size, err := openBlock(req.GetFileName())
 	if err != nil {
-		fmt.Println("Error listening:", err.Error())
-		return
+		return err
 	}
-	defer listener.Close()
-	fmt.Println("TCP [DOWNLOAD] Server started. Listening on port", port)
+	defer file.Close()
 
-	// Accept incoming connections
-	for {
-		conn, err := listener.Accept()
-		if err != nil {
-			fmt.Println("Error accepting connection:", err.Error())
-			return
-		}
-		fmt.Println("Client connected:", conn.RemoteAddr())
-	
-		// Handle incoming connection in a separate goroutine
-		go handleConnection(conn)
+	sent, err := sendFrames(file, 0, size, size, stream.Send)
+	if err != nil {
+		return err
 	}
+	fmt.Printf("Sent file %s (%d bytes)\n", req.GetFileName(), sent)
+	return nil
 }
 
-type server struct {
-	pb.UnimplementedDataKeeperServiceServer
+// fileChecksum returns the hex SHA-256 of a stored file
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-func  uploadFileToPort(filePath string, clientPort string) {
-	conn, err := net.Dial("tcp", clientPort)
+// sendFile streams a stored file to the Upload RPC of another data keeper. The receiver
+// checks the copy against checksum, which comes from the master rather than from this file.
+func sendFile(filePath string, fileName string, checksum string, grpcAddr string) error {
+	conn, release, err := connpool.Get(grpcAddr)
 	if err != nil {
-		fmt.Println("Error connecting:", err.Error())
-		return
+		return err
 	}
-	defer conn.Close()
+	defer release()
 
-	// Open the .mp4 file to be sent
 	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Println("Error opening file:", err.Error())
-		return
+		return err
 	}
 	defer file.Close()
 
-	// Send file data to server
-	_, err = io.Copy(conn, file)
+	stream, err := pb.NewDataKeeperServiceClient(conn).Upload(context.Background())
 	if err != nil {
-		fmt.Println("Error sending file:", err.Error())
-		return
+		return err
 	}
-	fmt.Println("File sent successfully!")
-}
-
-func (s *server) TransferFile(ctx context.Context, req *pb.FilePortRequest) (*pb.SuccessResponse, error) {
-	log.Printf("Received file %s to transfer on port %s\n", req.Filename, req.PortNumber)
-	filename := req.Filename
-	filepath := "datakeeper/" + id + "/" + filename + ".mp4"
-	uploadFileToPort(filepath, req.PortNumber)
-	return &pb.SuccessResponse{Success: true}, nil
+	buf := make([]byte, streamChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 || offset == 0 {
+			// Replicas carry no session id, which tells the master they are not client uploads
+			chunk := &pb.UploadRequest{Offset: offset, Data: buf[:n], Crc32C: crc32.Checksum(buf[:n], crc32cTable)}
+			if offset == 0 {
+				chunk.FileName = fileName
+				chunk.Sha256 = checksum
+			}
+			if err := stream.Send(chunk); err != nil {
+				break // the real error is returned by CloseAndRecv
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	return err
 }
 
 func (s *server) DownloadChunk(ctx context.Context, req *pb.DownloadChunkRequest) (*pb.DownloadChunkResponse, error) {
+	defer trackTransfer()()
 	fileName := req.GetFileName()
 	startByte := req.GetStartByte()
 	endByte := req.GetEndByte()
-	filePath := "datakeeper/" + id + "/" + fileName + ".mp4"
+	if !isValidBlockId(fileName) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block id %q", fileName)
+	}
+	filePath := blockPath(fileName)
 
 	// Open the file
 	file, err := os.Open(filePath)
@@ -205,16 +297,15 @@ func (s *server) DownloadChunk(ctx context.Context, req *pb.DownloadChunkRequest
 	}
 	defer file.Close()
 
-	// Seek to the start byte
-	_, err = file.Seek(startByte, 0)
-	if err != nil {
-		return nil, fmt.Errorf("failed to seek to start byte: %v", err)
+	// The whole range goes back in one message, so bigger ranges have to use ReadRange
+	chunkSize := endByte - startByte + 1
+	if startByte < 0 || chunkSize <= 0 || chunkSize > maxChunkSize {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid range %d-%d, ReadRange serves ranges over %d bytes", startByte, endByte, maxChunkSize)
 	}
 
-	// Read the chunk
-	chunkSize := endByte - startByte + 1
+	// Read the chunk. ReadFull keeps reading after short reads.
 	chunk := make([]byte, chunkSize)
-	n, err := file.Read(chunk)
+	n, err := io.ReadFull(io.NewSectionReader(file, startByte, chunkSize), chunk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read chunk from file: %v", err)
 	}
@@ -242,43 +333,58 @@ func (s *server) CheckFileExists(ctx context.Context, req *pb.CheckFileExistsReq
 }
 
 func (s *server) ReplicateFile(ctx context.Context, req *pb.ReplicateFileRequest) (*pb.SuccessResponse, error) {
-	fileName = req.FileName
-	tcpAddr := req.TcpAddr
-	grpcAddr := req.GrpcAddr
-	filePath := "datakeeper/" + id + "/" + fileName + ".mp4"
-	conn1,err1 := grpc.Dial(grpcAddr, grpc.WithInsecure())
-	if err1 != nil {
-		fmt.Println("did not connect:", err1)
-		return &pb.SuccessResponse{Success: false}, nil
-	}
-	defer conn1.Close()
-	d := pb.NewFileSaveServiceClient(conn1)
-	// for sending filename
-	resp1, err1 := d.SaveFile(context.Background(), &pb.FileSaveRequest{Filename: fileName})
-	if err1 != nil {
-		fmt.Println("Error calling SaveFile:", err1)
+	if err := replicateBlock(req.FileName, req.GrpcAddr, req.GetChecksum()); err != nil {
+		fmt.Println("Error replicating file:", err)
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, err
+		}
 		return &pb.SuccessResponse{Success: false}, nil
 	}
-	successMsg := resp1.GetSuccess()
-	if !successMsg {
-		fmt.Println("Error sending file name")
-	} else {
-		fmt.Println("File name sent")
-	}
-	uploadFileToPort(filePath, tcpAddr)
 	return &pb.SuccessResponse{Success: true}, nil
 }
 
-type fileSaveNameServer struct {
-	pb.UnimplementedFileSaveServiceServer
+// replicateBlock copies a stored block to the data keeper at grpcAddr
+func replicateBlock(fileName string, grpcAddr string, checksum string) error {
+	defer trackTransfer()()
+	if !isValidBlockId(fileName) {
+		return status.Errorf(codes.InvalidArgument, "invalid block id %q", fileName)
+	}
+	filePath := blockPath(fileName)
+	if checksum == "" {
+		// Blocks registered before checksums were recorded
+		var err error
+		if checksum, err = fileChecksum(filePath); err != nil {
+			return err
+		}
+	}
+	if err := sendFile(filePath, fileName, checksum, grpcAddr); err != nil {
+		return err
+	}
+	fmt.Println("File replicated to", grpcAddr)
+	return nil
 }
 
-func (s *fileSaveNameServer) SaveFile(ctx context.Context, req *pb.FileSaveRequest) (*pb.SuccessResponse, error) {
-	log.Printf("Received filename %s to save\n", req.Filename)
-	fileName = req.Filename
+func (s *server) DeleteFile(ctx context.Context, req *pb.DeleteFileRequest) (*pb.SuccessResponse, error) {
+	if err := deleteBlock(req.GetFileName()); err != nil {
+		return nil, status.Errorf(codes.Internal, "deleting file: %v", err)
+	}
 	return &pb.SuccessResponse{Success: true}, nil
 }
 
+// deleteBlock removes a stored block and its checksum
+func deleteBlock(fileName string) error {
+	if !isValidBlockId(fileName) {
+		return fmt.Errorf("invalid block id %q", fileName)
+	}
+	filePath := blockPath(fileName)
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	os.Remove(filePath + checksumSuffix)
+	fmt.Println("Deleted file:", fileName)
+	return nil
+}
+
 func uploadFile(port string) {
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
@@ -286,23 +392,21 @@ func uploadFile(port string) {
 	}
 	s := grpc.NewServer()
 	pb.RegisterDataKeeperServiceServer(s, &server{})
-	pb.RegisterFileSaveServiceServer(s, &fileSaveNameServer{})
 	
-	fmt.Println("GRPC [UPLOAD] Server started. Listening on port", port)
+	fmt.Println("GRPC Server started. Listening on port", port)
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
 
 func main() {
-	if len(os.Args) != 4 {
-		fmt.Println("Usage: program_name id arg1 arg2")
+	if len(os.Args) < 3 || len(os.Args) > 5 {
+		fmt.Println("Usage: program_name id grpc_port [rack] [zone]")
 		return
 	}
 
 	id = os.Args[1]
-	portNumber1 := os.Args[2]
-	portNumber2 := os.Args[3]
+	portNumber := os.Args[2]
 
 	idInt, Err := strconv.Atoi(id)
 	if Err != nil {
@@ -311,25 +415,35 @@ func main() {
 	}
 
 	fmt.Println("ID:", id)
-	fmt.Println("Download port number:", portNumber1)
-	fmt.Println("Upload port number:", portNumber2)
+	fmt.Println("Port number:", portNumber)
 
-	// Send id, portNumber1, portNumber2 to master
+	// Send id and portNumber to master
 	Err = godotenv.Load()
 	if Err != nil {
 		log.Fatal("Error loading .env file")
 	}
 
-	// Connecting with master
-	masterPort := os.Getenv("MASTER_PORT")
-	conn, err := grpc.Dial(masterPort, grpc.WithInsecure())
-	if err != nil {
-		fmt.Println("did not connect:", err)
-		return
+	// Rack and zone labels come from the arguments or from DATAKEEPER_RACK and DATAKEEPER_ZONE
+	rack := os.Getenv("DATAKEEPER_RACK")
+	zone := os.Getenv("DATAKEEPER_ZONE")
+	if len(os.Args) > 3 {
+		rack = os.Args[3]
+	}
+	if len(os.Args) > 4 {
+		zone = os.Args[4]
 	}
+	fmt.Printf("Rack: %q, zone: %q\n", rack, zone)
+	os.MkdirAll(filepath.Dir(blockPath("")), 0755)
+
+	// Blocks kept from an earlier run are reported with the join so the master can adopt them
+	blocks := recoverBlocks()
+
+	// Connecting with the master group
+	conn := masters.Dial(masters.Addresses())
 	defer conn.Close()
 	c := ms.NewMasterTrackerServiceClient(conn)
-	resp, err := c.Join(context.Background(), &ms.JoinRequest{Id: int32(idInt), Address: portNumber1, GrpcAddress: portNumber2})
+	join := &ms.JoinRequest{Id: int32(idInt), GrpcAddress: portNumber, Rack: rack, Zone: zone}
+	resp, err := c.Join(context.Background(), &ms.JoinRequest{Id: join.Id, GrpcAddress: join.GrpcAddress, Rack: join.Rack, Zone: join.Zone, Blocks: blocks})
 	if err != nil {
 		fmt.Println("Error calling Join:", err)
 		return
@@ -342,14 +456,17 @@ func main() {
 		fmt.Println("Joined the network successfully!")
 	}
 	
-	// Heartbeat
-	go waitAndPrint(idInt)
+	// Heartbeat over the same connection for as long as the data keeper runs
+	go sendHeartbeats(idInt, c, join)
+
+	// uploads, downloads and replication
+	go uploadFile(portNumber)
 
-	// download from a client
-	go listenForDownload(portNumber1)
+	// periodic checksum verification of the stored files
+	go scrub(idInt)
 
-	// upload to client
-	go uploadFile(portNumber2)
+	// staged uploads nobody came back to
+	go cleanStaging()
 
 	for {}
 }
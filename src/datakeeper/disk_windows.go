@@ -0,0 +1,8 @@
+package main
+
+import "errors"
+
+// diskSpace is not implemented on Windows; set DATAKEEPER_CAPACITY instead
+func diskSpace(path string) (int64, int64, error) {
+	return 0, 0, errors.New("disk space is not available on windows")
+}
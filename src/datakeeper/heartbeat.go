@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ms "src/grpc/master"
+)
+
+const (
+	// heartbeatTimeout bounds one heartbeat, including the retries over the master group
+	heartbeatTimeout = 5 * time.Second
+	// maxHeartbeatBackoff is the longest pause between heartbeats while the masters cannot be reached
+	maxHeartbeatBackoff = 30 * time.Second
+)
+
+// sendHeartbeats keeps sending heartbeats over the long-lived connection c for as long as the data keeper
+// runs. When the masters cannot be reached it backs off up to maxHeartbeatBackoff and carries on once
+// they are back. The commands piggybacked on the responses are run as they arrive.
+func sendHeartbeats(id int, c ms.MasterTrackerServiceClient, join *ms.JoinRequest) {
+	var lastReport time.Time
+	wait := heartbeatInterval()
+	failures := 0
+	for {
+		time.Sleep(wait)
+
+		// Every few heartbeats carry the full block list
+		fullReport := time.Since(lastReport) >= blockReportInterval()
+		ctx, cancel := context.WithTimeout(context.Background(), heartbeatTimeout)
+		resp, err := c.Heartbeat(ctx, heartbeatRequest(id, fullReport))
+		cancel()
+		if err != nil {
+			if failures == 0 {
+				fmt.Println("Error calling Heartbeat:", err)
+			}
+			failures++
+			wait = min(wait*2, maxHeartbeatBackoff)
+			continue
+		}
+		if failures > 0 {
+			fmt.Printf("Reached the master again after %d failed heartbeats\n", failures)
+		}
+		failures = 0
+		wait = heartbeatInterval()
+		if fullReport {
+			lastReport = time.Now()
+		}
+
+		for _, command := range resp.GetCommands() {
+			if command.GetType() == ms.Command_REJOIN && rejoin(c, join) == nil {
+				// The new master has not seen any block yet
+				lastReport = time.Time{}
+				continue
+			}
+			runCommand(id, command)
+		}
+	}
+}
+
+// rejoin joins the master group again with the blocks stored now
+func rejoin(c ms.MasterTrackerServiceClient, join *ms.JoinRequest) error {
+	req := &ms.JoinRequest{Id: join.GetId(), GrpcAddress: join.GetGrpcAddress(), Rack: join.GetRack(), Zone: join.GetZone(), Blocks: storedInventory()}
+	resp, err := c.Join(context.Background(), req)
+	if err == nil && !resp.GetSuccess() {
+		err = fmt.Errorf("the master refused the join")
+	}
+	if err != nil {
+		fmt.Println("Error joining the network again:", err)
+		return err
+	}
+	fmt.Println("Joined the network again")
+	return nil
+}
+
+// runCommand carries out a command received with a heartbeat response. Copies and scrubs run in the
+// background so they do not hold up the heartbeats.
+func runCommand(id int, command *ms.Command) {
+	blockId := command.GetBlockId()
+	switch command.GetType() {
+	case ms.Command_REPLICATE:
+		go func() {
+			if err := replicateBlock(blockId, command.GetGrpcAddress(), command.GetChecksum()); err != nil {
+				fmt.Printf("Error replicating %s to %s: %v\n", blockId, command.GetGrpcAddress(), err)
+			}
+		}()
+	case ms.Command_DELETE:
+		if err := deleteBlock(blockId); err != nil {
+			fmt.Printf("Error deleting %s: %v\n", blockId, err)
+		}
+	case ms.Command_SCRUB:
+		if blockId == "" {
+			go scrubAll(id)
+		} else if isValidBlockId(blockId) {
+			go scrubBlock(id, blockId)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ms "src/grpc/master"
+)
+
+// inFlight counts the uploads, downloads and replications being served
+var inFlight atomic.Int32
+
+// trackTransfer counts a transfer until the returned function is called
+func trackTransfer() func() {
+	inFlight.Add(1)
+	return func() { inFlight.Add(-1) }
+}
+
+// storedBlocks lists the blocks in the storage folder and their total size
+func storedBlocks() ([]string, int64) {
+	entries, err := os.ReadDir(filepath.Dir(blockPath("")))
+	if err != nil {
+		return nil, 0
+	}
+	blocks := make([]string, 0, len(entries))
+	var used int64
+	for _, entry := range entries {
+		if entry.IsDir() || !isValidBlockId(entry.Name()) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			used += info.Size()
+		}
+		blocks = append(blocks, entry.Name())
+	}
+	return blocks, used
+}
+
+// usage returns the bytes this data keeper may use for blocks, the bytes used and the bytes still available.
+// DATAKEEPER_CAPACITY caps the capacity below the size of the disk.
+func usage(used int64) (int64, int64) {
+	capacity, _ := strconv.ParseInt(os.Getenv("DATAKEEPER_CAPACITY"), 10, 64)
+	diskSize, diskFree, err := diskSpace(filepath.Dir(blockPath("")))
+	if err != nil {
+		return capacity, max(capacity-used, 0)
+	}
+	if capacity <= 0 || capacity > diskSize {
+		capacity = diskSize
+	}
+	return capacity, max(min(capacity-used, diskFree), 0)
+}
+
+// blockReportInterval reads BLOCK_REPORT_INTERVAL (in seconds), how often a heartbeat carries the full block list
+func blockReportInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("BLOCK_REPORT_INTERVAL"))
+	if err != nil || seconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// heartbeatInterval reads HEARTBEAT_INTERVAL (in seconds), how often heartbeats are sent
+func heartbeatInterval() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("HEARTBEAT_INTERVAL"), 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// heartbeatRequest describes the load of this data keeper, with the list of stored blocks when fullReport is set
+func heartbeatRequest(id int, fullReport bool) *ms.HeartbeatRequest {
+	blocks, used := storedBlocks()
+	capacity, available := usage(used)
+	req := &ms.HeartbeatRequest{
+		DataNodeId: int32(id),
+		Capacity:   capacity,
+		Used:       used,
+		Available:  available,
+		InFlight:   inFlight.Load(),
+		FullReport: fullReport,
+	}
+	if fullReport {
+		req.Blocks = blocks
+	}
+	return req
+}
+
+// recoverBlocks checks the storage folder after a restart and returns the blocks that are safe to serve.
+// Temporary files of interrupted uploads are removed, and so is every block that no longer matches
+// its checksum. A block whose checksum file was never written is hashed and the file written now;
+// the master decides whether the block belongs to a file.
+func recoverBlocks() []*ms.StoredBlock {
+	dir := filepath.Dir(blockPath(""))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	blocks := make([]*ms.StoredBlock, 0, len(entries))
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		filePath := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".part") {
+			os.Remove(filePath)
+			removed++
+			continue
+		}
+		if strings.HasSuffix(name, checksumSuffix) {
+			// A checksum file without its block is left over from a delete
+			if _, err := os.Stat(strings.TrimSuffix(filePath, checksumSuffix)); os.IsNotExist(err) {
+				os.Remove(filePath)
+			}
+			continue
+		}
+		if !isValidBlockId(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		checksum, err := fileChecksum(filePath)
+		if err != nil {
+			fmt.Printf("[RECOVERY] Cannot read %s: %v\n", name, err)
+			continue
+		}
+		expected, err := readChecksum(filePath)
+		if err != nil {
+			if err := writeChecksum(filePath, checksum); err != nil {
+				fmt.Printf("[RECOVERY] Cannot write the checksum of %s: %v\n", name, err)
+			}
+		} else if expected != checksum {
+			fmt.Printf("[RECOVERY] %s is corrupt: checksum %s, expected %s\n", name, checksum, expected)
+			os.Remove(filePath)
+			os.Remove(filePath + checksumSuffix)
+			removed++
+			continue
+		}
+		blocks = append(blocks, &ms.StoredBlock{BlockId: name, FilePath: blockPath(name), Size: info.Size(), Checksum: checksum})
+	}
+	fmt.Printf("[RECOVERY] Found %d blocks, removed %d unusable files\n", len(blocks), removed)
+	return blocks
+}
+
+// storedInventory lists the stored blocks with the checksums kept next to them, without re-reading the
+// blocks or touching uploads in progress. Blocks without a checksum are left out.
+func storedInventory() []*ms.StoredBlock {
+	dir := filepath.Dir(blockPath(""))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	blocks := make([]*ms.StoredBlock, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isValidBlockId(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		checksum, err := readChecksum(blockPath(entry.Name()))
+		if err != nil {
+			continue
+		}
+		blocks = append(blocks, &ms.StoredBlock{BlockId: entry.Name(), FilePath: blockPath(entry.Name()), Size: info.Size(), Checksum: checksum})
+	}
+	return blocks
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	pb "src/grpc/datakeeper"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxChunkSize keeps DownloadChunk responses under the 4 MiB gRPC message limit
+const maxChunkSize = 3 << 20
+
+// framePool holds the buffers Download and ReadRange read frames into, so reads do not allocate per request
+var framePool = sync.Pool{New: func() interface{} {
+	buf := make([]byte, streamChunkSize)
+	return &buf
+}}
+
+// openBlock opens a stored block and returns its size
+func openBlock(blockId string) (*os.File, int64, error) {
+	if !isValidBlockId(blockId) {
+		return nil, 0, status.Errorf(codes.InvalidArgument, "invalid block id %q", blockId)
+	}
+	file, err := os.Open(blockPath(blockId))
+	if os.IsNotExist(err) {
+		return nil, 0, status.Errorf(codes.NotFound, "file %s not found", blockId)
+	} else if err != nil {
+		return nil, 0, status.Errorf(codes.Internal, "opening file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, status.Errorf(codes.Internal, "getting file info: %v", err)
+	}
+	return file, info.Size(), nil
+}
+
+// sendFrames sends length bytes of file from offset in frames of at most streamChunkSize bytes and returns
+// how many were sent. It stops early at the end of the file. The first frame carries the file size and is
+// sent even when the range is empty.
+func sendFrames(file *os.File, offset int64, length int64, fileSize int64, send func(*pb.DownloadResponse) error) (int64, error) {
+	bufp := framePool.Get().(*[]byte)
+	defer framePool.Put(bufp)
+	buf := *bufp
+
+	reader := io.NewSectionReader(file, offset, length)
+	var sent int64
+	for {
+		// ReadFull keeps reading after short reads, so only the last frame is smaller than the buffer
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 || sent == 0 {
+			// gRPC does not promise that Send is done with a message when it returns (stats handlers may read
+			// it later), so the frame gets its own copy and the pooled buffer only serves the file reads
+			data := append([]byte(nil), buf[:n]...)
+			frame := &pb.DownloadResponse{Offset: offset + sent, Data: data, Crc32C: crc32.Checksum(data, crc32cTable)}
+			if sent == 0 {
+				frame.FileSize = fileSize
+			}
+			if err := send(frame); err != nil {
+				return sent, err
+			}
+			sent += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sent, nil
+		}
+		if err != nil {
+			return sent, status.Errorf(codes.Internal, "reading file: %v", err)
+		}
+	}
+}
+
+// ReadRange streams a byte range of a stored block. A length of 0, or one past the end of the block,
+// reads to the end.
+func (s *server) ReadRange(req *pb.ReadRangeRequest, stream pb.DataKeeperService_ReadRangeServer) error {
+	defer trackTransfer()()
+	offset, length := req.GetOffset(), req.GetLength()
+	if offset < 0 || length < 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid range of %d bytes at offset %d", length, offset)
+	}
+	file, size, err := openBlock(req.GetFileName())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if offset > size {
+		return status.Errorf(codes.OutOfRange, "offset %d is past the end of %s (%d bytes)", offset, req.GetFileName(), size)
+	}
+	if length == 0 || length > size-offset {
+		length = size - offset
+	}
+	_, err = sendFrames(file, offset, length, size, stream.Send)
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "src/grpc/datakeeper"
+)
+
+func TestSendFrames(t *testing.T) {
+	content := make([]byte, 2*streamChunkSize+10)
+	rand.Read(content)
+	path := filepath.Join(t.TempDir(), "b0")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	size := int64(len(content))
+
+	for _, c := range []struct {
+		name           string
+		offset, length int64
+		frames         int
+	}{
+		{"whole file", 0, size, 3},
+		{"within one frame", 5, 20, 1},
+		{"across frames", streamChunkSize - 5, streamChunkSize + 10, 2},
+		{"past the end", size - 4, 100, 1},
+		{"empty range", size, 0, 1},
+	} {
+		// The frames are kept until the range is sent, so a buffer reused under them shows up
+		var frames []*pb.DownloadResponse
+		sent, err := sendFrames(file, c.offset, c.length, size, func(frame *pb.DownloadResponse) error {
+			frames = append(frames, frame)
+			return nil
+		})
+		want := content[c.offset:min(c.offset+c.length, size)]
+		if err != nil || sent != int64(len(want)) || len(frames) != c.frames {
+			t.Errorf("%s: sent %d bytes in %d frames, %v; want %d in %d", c.name, sent, len(frames), err, len(want), c.frames)
+			continue
+		}
+		var got []byte
+		for i, frame := range frames {
+			if frame.GetOffset() != c.offset+int64(len(got)) || (frame.GetFileSize() == size) != (i == 0) {
+				t.Errorf("%s: frame %d at offset %d with file size %d", c.name, i, frame.GetOffset(), frame.GetFileSize())
+			}
+			got = append(got, frame.GetData()...)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: frames do not hold the range", c.name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ms "src/grpc/master"
+
+	"src/masters"
+)
+
+// checksumSuffix names the file next to every stored file that holds its hex SHA-256
+const checksumSuffix = ".sha256"
+
+func writeChecksum(filePath string, checksum string) error {
+	return os.WriteFile(filePath+checksumSuffix, []byte(checksum), 0644)
+}
+
+func readChecksum(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath + checksumSuffix)
+	return strings.TrimSpace(string(data)), err
+}
+
+// scrubInterval reads SCRUB_INTERVAL (in seconds), the pause between two passes over the stored files
+func scrubInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("SCRUB_INTERVAL"))
+	if err != nil || seconds <= 0 {
+		return time.Hour
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// scrubRate reads SCRUB_RATE, the bytes per second the scrubber may read so it does not starve client I/O
+func scrubRate() int64 {
+	rate, err := strconv.ParseInt(os.Getenv("SCRUB_RATE"), 10, 64)
+	if err != nil || rate <= 0 {
+		return 8 << 20
+	}
+	return rate
+}
+
+// quarantineDir holds corrupt files until the master has been told about them. Its name is not a valid block id.
+func quarantineDir() string {
+	return filepath.Join(filepath.Dir(blockPath("")), ".quarantine")
+}
+
+// scrub periodically re-reads every stored file and reports the ones that no longer match their checksum
+func scrub(id int) {
+	for {
+		time.Sleep(scrubInterval())
+		scrubAll(id)
+	}
+}
+
+// scrubbing is set while a pass over every stored file runs
+var scrubbing atomic.Bool
+
+// scrubAll checks every stored file once, unless a pass is already running
+func scrubAll(id int) {
+	if !scrubbing.CompareAndSwap(false, true) {
+		return
+	}
+	defer scrubbing.Store(false)
+	// Corrupt files whose report failed before are reported again
+	if quarantined, err := os.ReadDir(quarantineDir()); err == nil {
+		for _, entry := range quarantined {
+			reportQuarantined(id, entry.Name())
+		}
+	}
+	entries, err := os.ReadDir(filepath.Dir(blockPath("")))
+	if err != nil {
+		return
+	}
+	checked, corrupt := 0, 0
+	for _, entry := range entries {
+		if entry.IsDir() || !isValidBlockId(entry.Name()) {
+			continue
+		}
+		ok, bad := scrubBlock(id, entry.Name())
+		if ok {
+			checked++
+		}
+		if bad {
+			corrupt++
+		}
+	}
+	fmt.Printf("[SCRUB] Checked %d files, %d corrupt\n", checked, corrupt)
+}
+
+// scrubBlock checks one stored file against its checksum, quarantining and reporting it if it is corrupt.
+// It returns whether the file could be checked and whether it was corrupt.
+func scrubBlock(id int, blockId string) (bool, bool) {
+	filePath := blockPath(blockId)
+	expected, err := readChecksum(filePath)
+	if err != nil {
+		// Files stored before checksums were kept cannot be verified
+		return false, false
+	}
+	checksum, err := throttledChecksum(filePath, scrubRate())
+	if err != nil {
+		return false, false
+	}
+	if checksum == expected {
+		return true, false
+	}
+
+	fmt.Printf("[SCRUB] %s is corrupt: checksum %s, expected %s\n", blockId, checksum, expected)
+	// Stop serving the bad copy before the master arranges a new one, but keep it until the report
+	// gets through, so a failed report is retried with the next pass
+	os.MkdirAll(quarantineDir(), 0755)
+	if err := os.Rename(filePath, filepath.Join(quarantineDir(), blockId)); err != nil {
+		fmt.Println("Error quarantining file:", err)
+		return true, true
+	}
+	os.Remove(filePath + checksumSuffix)
+	reportQuarantined(id, blockId)
+	return true, true
+}
+
+// reportQuarantined tells the master about a quarantined file and deletes the file once the master knows
+func reportQuarantined(id int, blockId string) {
+	if err := reportCorruptBlock(id, blockId); err != nil {
+		fmt.Println("Error calling ReportCorruptBlock:", err)
+		return
+	}
+	os.Remove(filepath.Join(quarantineDir(), blockId))
+}
+
+// throttledChecksum hashes a file without reading more than rate bytes per second
+func throttledChecksum(filePath string, rate int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	buf := make([]byte, streamChunkSize)
+	start := time.Now()
+	var read int64
+	for {
+		n, err := file.Read(buf)
+		hash.Write(buf[:n])
+		read += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		// Sleep until the bytes read so far fit the rate
+		if wait := time.Duration(read*int64(time.Second)/rate) - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func reportCorruptBlock(id int, blockId string) error {
+	conn := masters.Dial(masters.Addresses())
+	defer conn.Close()
+	c := ms.NewMasterTrackerServiceClient(conn)
+	_, err := c.ReportCorruptBlock(context.Background(), &ms.CorruptBlockRequest{DataNodeId: int32(id), BlockId: blockId})
+	return err
+}
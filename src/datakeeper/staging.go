@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "src/grpc/datakeeper"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Blocks uploaded with UploadPart are staged under their session until the client commits them.
+// The staging folder is skipped by recovery, so an upload can resume after a restart too.
+
+// stagingTTL reads UPLOAD_STAGING_TTL (in seconds), how long staged bytes are kept without being
+// added to. The default matches the lifetime of upload sessions on the master.
+func stagingTTL() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("UPLOAD_STAGING_TTL"))
+	if err != nil || seconds <= 0 {
+		return time.Hour
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// stagingDir is the folder of staged blocks. Its name is not a valid block id.
+func stagingDir() string {
+	return filepath.Join(filepath.Dir(blockPath("")), ".staging")
+}
+
+func stagingPath(sessionId string, blockId string) string {
+	return filepath.Join(stagingDir(), sessionId+"-"+blockId+".part")
+}
+
+// checkStagingKey validates the session and block an upload is staged under
+func checkStagingKey(sessionId string, blockId string) error {
+	if !isValidBlockId(blockId) {
+		return status.Errorf(codes.InvalidArgument, "invalid block id %q", blockId)
+	}
+	valid := sessionId != ""
+	for _, r := range sessionId {
+		valid = valid && (r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}
+	if !valid {
+		return status.Errorf(codes.InvalidArgument, "invalid session id %q", sessionId)
+	}
+	return nil
+}
+
+// staging holds the staged blocks being written or committed, one call at a time per block
+var staging = struct {
+	mu     sync.Mutex
+	active map[string]bool
+}{active: make(map[string]bool)}
+
+func acquireStaged(path string) error {
+	staging.mu.Lock()
+	defer staging.mu.Unlock()
+	if staging.active[path] {
+		return status.Errorf(codes.Unavailable, "%s is already being uploaded", filepath.Base(path))
+	}
+	staging.active[path] = true
+	return nil
+}
+
+func releaseStaged(path string) {
+	staging.mu.Lock()
+	defer staging.mu.Unlock()
+	delete(staging.active, path)
+}
+
+// UploadPart appends to the staged bytes of a block. Chunks must follow each other from the committed
+// offset. Whatever arrived intact before the stream broke stays staged, and the reply carries the new
+// committed offset.
+func (s *server) UploadPart(stream pb.DataKeeperService_UploadPartServer) error {
+	defer trackTransfer()()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	sessionId, fileName := first.GetSessionId(), first.GetFileName()
+	if err := checkStagingKey(sessionId, fileName); err != nil {
+		return err
+	}
+	path := stagingPath(sessionId, fileName)
+	if err := acquireStaged(path); err != nil {
+		return err
+	}
+	defer releaseStaged(path)
+
+	os.MkdirAll(stagingDir(), 0755)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return status.Errorf(codes.Internal, "staging file: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return status.Errorf(codes.Internal, "staging file: %v", err)
+	}
+	committed := info.Size()
+	if first.GetOffset() != committed {
+		return status.Errorf(codes.FailedPrecondition, "%s is staged up to offset %d, not %d", fileName, committed, first.GetOffset())
+	}
+
+	var recvErr error
+	for chunk := first; ; {
+		if chunk.GetOffset() != committed {
+			recvErr = status.Errorf(codes.InvalidArgument, "chunk at offset %d, expected %d", chunk.GetOffset(), committed)
+			break
+		}
+		if crc32.Checksum(chunk.GetData(), crc32cTable) != chunk.GetCrc32C() {
+			recvErr = status.Errorf(codes.DataLoss, "checksum mismatch in chunk at offset %d", chunk.GetOffset())
+			break
+		}
+		if _, err := file.WriteAt(chunk.GetData(), committed); err != nil {
+			recvErr = status.Errorf(codes.Internal, "writing file: %v", err)
+			break
+		}
+		committed += int64(len(chunk.GetData()))
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+	}
+
+	// A write that failed halfway is cut off, so the staged bytes always end at the committed offset
+	if err := file.Truncate(committed); err != nil {
+		return status.Errorf(codes.Internal, "staging file: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return status.Errorf(codes.Internal, "syncing file: %v", err)
+	}
+	if recvErr != nil {
+		fmt.Printf("Upload of %s stopped at offset %d: %v\n", fileName, committed, recvErr)
+		return recvErr
+	}
+	return stream.SendAndClose(&pb.UploadPartResponse{CommittedOffset: committed})
+}
+
+// UploadStatus reports where an upload of a block resumes. Once the block is committed, that is its full size.
+func (s *server) UploadStatus(ctx context.Context, req *pb.UploadStatusRequest) (*pb.UploadPartResponse, error) {
+	if err := checkStagingKey(req.GetSessionId(), req.GetFileName()); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(stagingPath(req.GetSessionId(), req.GetFileName()))
+	if os.IsNotExist(err) {
+		info, err = os.Stat(blockPath(req.GetFileName()))
+		if os.IsNotExist(err) {
+			return &pb.UploadPartResponse{CommittedOffset: 0}, nil
+		}
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "staging file: %v", err)
+	}
+	return &pb.UploadPartResponse{CommittedOffset: info.Size()}, nil
+}
+
+// CommitUpload stores a staged block once it has the announced size and checksum. A block that does
+// not match its checksum is dropped and has to be uploaded again. Committing a stored block again
+// only registers it again, so a client whose reply got lost can retry.
+func (s *server) CommitUpload(ctx context.Context, req *pb.CommitUploadRequest) (*pb.UploadResponse, error) {
+	defer trackTransfer()()
+	sessionId, fileName, expected := req.GetSessionId(), req.GetFileName(), req.GetSha256()
+	if err := checkStagingKey(sessionId, fileName); err != nil {
+		return nil, err
+	}
+	if expected == "" {
+		return nil, status.Error(codes.InvalidArgument, "a commit must carry the checksum of the block")
+	}
+	path := stagingPath(sessionId, fileName)
+	if err := acquireStaged(path); err != nil {
+		return nil, err
+	}
+	defer releaseStaged(path)
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		filePath := blockPath(fileName)
+		stored, statErr := os.Stat(filePath)
+		checksum, readErr := readChecksum(filePath)
+		if statErr != nil || readErr != nil || checksum != expected || stored.Size() != req.GetSize() {
+			return nil, status.Errorf(codes.FailedPrecondition, "nothing staged for %s", fileName)
+		}
+		if err := registerFile(fileName, stored.Size(), checksum, sessionId); err != nil {
+			fmt.Println("Error calling RegisterFile:", err)
+			if code := status.Code(err); code == codes.DataLoss || code == codes.AlreadyExists || code == codes.NotFound || code == codes.Aborted {
+				return nil, err
+			}
+			return nil, notRegistered(fileName, err)
+		}
+		return &pb.UploadResponse{Success: true, Size: stored.Size(), Sha256: checksum}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "staging file: %v", err)
+	}
+	if info.Size() != req.GetSize() {
+		return nil, status.Errorf(codes.FailedPrecondition, "%d bytes of %s are staged, expected %d", info.Size(), fileName, req.GetSize())
+	}
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "reading file: %v", err)
+	}
+	if checksum != expected {
+		fmt.Printf("Rejected %s: checksum %s, expected %s\n", fileName, checksum, expected)
+		os.Remove(path)
+		return nil, status.Errorf(codes.DataLoss, "checksum mismatch for %s", fileName)
+	}
+	if err := storeBlock(path, fileName, info.Size(), checksum, sessionId); err != nil {
+		return nil, err
+	}
+	return &pb.UploadResponse{Success: true, Size: info.Size(), Sha256: checksum}, nil
+}
+
+// cleanStaging removes the staged blocks of uploads that were abandoned
+func cleanStaging() {
+	for {
+		time.Sleep(time.Minute)
+		entries, err := os.ReadDir(stagingDir())
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !strings.HasSuffix(entry.Name(), ".part") || time.Since(info.ModTime()) < stagingTTL() {
+				continue
+			}
+			path := filepath.Join(stagingDir(), entry.Name())
+			if acquireStaged(path) != nil {
+				continue
+			}
+			os.Remove(path)
+			releaseStaged(path)
+			fmt.Println("Removed abandoned upload:", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "src/grpc/datakeeper"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// partStream feeds chunks to UploadPart, then ends with end (io.EOF when nil)
+type partStream struct {
+	grpc.ServerStream
+	chunks []*pb.UploadRequest
+	end    error
+	reply  *pb.UploadPartResponse
+}
+
+func (s *partStream) Recv() (*pb.UploadRequest, error) {
+	if len(s.chunks) == 0 {
+		if s.end != nil {
+			return nil, s.end
+		}
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *partStream) SendAndClose(reply *pb.UploadPartResponse) error {
+	s.reply = reply
+	return nil
+}
+
+func (s *partStream) Context() context.Context {
+	return context.Background()
+}
+
+func chunk(offset int64, data string) *pb.UploadRequest {
+	return &pb.UploadRequest{SessionId: "s1", FileName: "b0", Offset: offset, Data: []byte(data), Crc32C: crc32.Checksum([]byte(data), crc32cTable)}
+}
+
+// useBlocksDir stores the blocks of the test under a temporary folder
+func useBlocksDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	oldId := id
+	id = "1"
+	t.Cleanup(func() {
+		id = oldId
+		os.Chdir(wd)
+	})
+	os.MkdirAll(filepath.Dir(blockPath("b0")), 0755)
+}
+
+func TestUploadPart(t *testing.T) {
+	useBlocksDir(t)
+	corrupt := chunk(10, "kl")
+	corrupt.Crc32C++
+
+	// Each case continues from the bytes staged by the ones before it
+	for _, c := range []struct {
+		name   string
+		chunks []*pb.UploadRequest
+		end    error
+		code   codes.Code
+		staged string
+	}{
+		{"first chunks", []*pb.UploadRequest{chunk(0, "abc"), chunk(3, "def")}, nil, codes.OK, "abcdef"},
+		{"resume at the committed offset", []*pb.UploadRequest{chunk(6, "gh")}, nil, codes.OK, "abcdefgh"},
+		{"resume at another offset", []*pb.UploadRequest{chunk(4, "ef")}, nil, codes.FailedPrecondition, "abcdefgh"},
+		{"gap between chunks", []*pb.UploadRequest{chunk(8, "ij"), chunk(11, "x")}, nil, codes.InvalidArgument, "abcdefghij"},
+		{"corrupt chunk", []*pb.UploadRequest{corrupt}, nil, codes.DataLoss, "abcdefghij"},
+		{"broken stream keeps what arrived", []*pb.UploadRequest{chunk(10, "kl")}, status.Error(codes.Canceled, "gone"), codes.Canceled, "abcdefghijkl"},
+	} {
+		stream := &partStream{chunks: c.chunks, end: c.end}
+		err := (&server{}).UploadPart(stream)
+		if code := status.Code(err); code != c.code {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.code)
+		}
+		if c.code == codes.OK && stream.reply.GetCommittedOffset() != int64(len(c.staged)) {
+			t.Errorf("%s: committed offset %d, want %d", c.name, stream.reply.GetCommittedOffset(), len(c.staged))
+		}
+		if data, _ := os.ReadFile(stagingPath("s1", "b0")); string(data) != c.staged {
+			t.Errorf("%s: staged %q, want %q", c.name, data, c.staged)
+		}
+		resp, err := (&server{}).UploadStatus(context.Background(), &pb.UploadStatusRequest{SessionId: "s1", FileName: "b0"})
+		if err != nil || resp.GetCommittedOffset() != int64(len(c.staged)) {
+			t.Errorf("%s: upload status %v, %v, want offset %d", c.name, resp, err, len(c.staged))
+		}
+	}
+
+	for _, req := range []*pb.UploadRequest{{SessionId: "s1", FileName: "../b0"}, {SessionId: "s/1", FileName: "b0"}, {FileName: "b0"}} {
+		if err := (&server{}).UploadPart(&partStream{chunks: []*pb.UploadRequest{req}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("staged under session %q and block %q: %v", req.SessionId, req.FileName, err)
+		}
+	}
+}
+
+func TestCommitUploadRejects(t *testing.T) {
+	useBlocksDir(t)
+	if err := (&server{}).UploadPart(&partStream{chunks: []*pb.UploadRequest{chunk(0, "abc")}}); err != nil {
+		t.Fatal(err)
+	}
+	commit := func(size int64, checksum string) error {
+		_, err := (&server{}).CommitUpload(context.Background(), &pb.CommitUploadRequest{SessionId: "s1", FileName: "b0", Size: size, Sha256: checksum})
+		return err
+	}
+	// Each of these commits fails before the master is called
+	for _, c := range []struct {
+		name     string
+		size     int64
+		checksum string
+		code     codes.Code
+		staged   bool
+	}{
+		{"without a checksum", 3, "", codes.InvalidArgument, true},
+		{"with a size not staged yet", 5, "ff", codes.FailedPrecondition, true},
+		{"with another checksum", 3, "ff", codes.DataLoss, false},
+		{"once the staged bytes were dropped", 3, "ff", codes.FailedPrecondition, false},
+	} {
+		if err := commit(c.size, c.checksum); status.Code(err) != c.code {
+			t.Errorf("commit %s: got %v, want %v", c.name, err, c.code)
+		}
+		if _, err := os.Stat(stagingPath("s1", "b0")); errors.Is(err, os.ErrNotExist) == c.staged {
+			t.Errorf("commit %s: staged file exists %v, want %v", c.name, err == nil, c.staged)
+		}
+	}
+}
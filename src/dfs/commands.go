@@ -0,0 +1,391 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"src/dfsclient"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const invalidPathMessage = "invalid path %q: paths start with / and each part can contain only letters, numbers, dots, underscores and hyphens"
+
+// parseFlags parses the flags of a subcommand and checks that it got between min and max arguments
+// (max < 0 for no limit)
+func parseFlags(flags *flag.FlagSet, args []string, min int, max int) error {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return usagef("%s: %v", flags.Name(), err)
+	}
+	if flags.NArg() < min || (max >= 0 && flags.NArg() > max) {
+		return usagef("%s: wrong number of arguments", flags.Name())
+	}
+	return nil
+}
+
+// isValidPath checks an absolute path like /projects/demo/clip
+func isValidPath(filePath string) bool {
+	match, _ := regexp.MatchString("^(/[a-zA-Z0-9_.-]+)+$", filePath)
+	return match
+}
+
+// checkPath rejects invalid paths before they reach the master. The root is only allowed for directories.
+func checkPath(filePath string, dir bool) error {
+	if (dir && filePath == "/") || isValidPath(filePath) {
+		return nil
+	}
+	return usagef(invalidPathMessage, filePath)
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+type putResult struct {
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	Blocks      int    `json:"blocks"`
+	Checksum    string `json:"checksum"`
+	ContentType string `json:"contentType"`
+}
+
+func put(s *session, args []string) error {
+	flags := flag.NewFlagSet("put", flag.ContinueOnError)
+	replication := flags.Int("r", 0, "copies to keep (0 follows the directory)")
+	contentType := flags.String("type", "", "MIME type (guessed when empty)")
+	if err := parseFlags(flags, args, 2, 2); err != nil {
+		return err
+	}
+	localPath, remotePath := flags.Arg(0), flags.Arg(1)
+	if err := checkPath(remotePath, false); err != nil {
+		return err
+	}
+	if *replication < 0 {
+		return usagef("put: the replication factor cannot be negative")
+	}
+	opts := dfsclient.CreateOptions{Replication: int32(*replication), ContentType: *contentType}
+	if opts.ContentType == "" {
+		opts.ContentType = mime.TypeByExtension(filepath.Ext(localPath))
+	}
+
+	if localPath == "-" {
+		w, err := s.client.CreateWithOptions(s.ctx, remotePath, opts)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, os.Stdin); err != nil {
+			w.Close()
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+	} else {
+		file, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if err := s.client.Upload(s.ctx, remotePath, file, info.Size(), opts); err != nil {
+			return err
+		}
+	}
+
+	info, err := s.client.Stat(s.ctx, remotePath)
+	if err != nil {
+		return err
+	}
+	result := putResult{Path: info.Name, Size: info.Size, Blocks: len(info.Blocks), Checksum: info.Checksum, ContentType: info.ContentType}
+	s.print(result, func() {
+		fmt.Printf("Stored %s (%d bytes in %d blocks)\n", result.Path, result.Size, result.Blocks)
+	})
+	return nil
+}
+
+type getResult struct {
+	Path        string `json:"path"`
+	Local       string `json:"local"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	ContentType string `json:"contentType"`
+}
+
+// fileChecksum returns the hex SHA-256 of the first size bytes of a local file
+func fileChecksum(file *os.File, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, 0, size)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func get(s *session, args []string) error {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	force := flags.Bool("f", false, "overwrite the local file")
+	if err := parseFlags(flags, args, 1, 2); err != nil {
+		return err
+	}
+	remotePath, localPath := flags.Arg(0), path.Base(flags.Arg(0))
+	if flags.NArg() == 2 {
+		localPath = flags.Arg(1)
+	}
+	if localPath == "-" {
+		return cat(s, []string{remotePath})
+	}
+	if err := checkPath(remotePath, false); err != nil {
+		return err
+	}
+	if _, err := os.Stat(localPath); err == nil && !*force {
+		return fmt.Errorf("%s already exists, use -f to overwrite it: %w", localPath, os.ErrExist)
+	}
+	info, err := s.client.Stat(s.ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	// Blocks are written in place into a partial file that only replaces localPath once it is verified
+	partPath := localPath + ".part"
+	local, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(partPath)
+	defer local.Close()
+	if err := local.Truncate(info.Size); err != nil {
+		return err
+	}
+	if err := s.client.Download(s.ctx, remotePath, local); err != nil {
+		return err
+	}
+	checksum, err := fileChecksum(local, info.Size)
+	if err != nil {
+		return err
+	}
+	if info.Checksum != "" && checksum != info.Checksum {
+		return status.Errorf(codes.DataLoss, "checksum %s, expected %s", checksum, info.Checksum)
+	}
+	if err := local.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(partPath, localPath); err != nil {
+		return err
+	}
+
+	result := getResult{Path: remotePath, Local: localPath, Size: info.Size, Checksum: checksum, ContentType: info.ContentType}
+	s.print(result, func() {
+		fmt.Printf("Saved %s to %s (%d bytes)\n", remotePath, localPath, info.Size)
+	})
+	return nil
+}
+
+func cat(s *session, args []string) error {
+	flags := flag.NewFlagSet("cat", flag.ContinueOnError)
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	if err := checkPath(flags.Arg(0), false); err != nil {
+		return err
+	}
+	r, err := s.client.Open(s.ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(os.Stdout, r)
+	return err
+}
+
+type listEntry struct {
+	Path        string `json:"path"`
+	IsDir       bool   `json:"isDir"`
+	Size        int64  `json:"size"`
+	ModifiedAt  string `json:"modifiedAt"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+func ls(s *session, args []string) error {
+	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
+	recursive := flags.Bool("R", false, "list subdirectories too")
+	if err := parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+	dirPath := "/"
+	if flags.NArg() == 1 {
+		dirPath = flags.Arg(0)
+	}
+	if err := checkPath(dirPath, true); err != nil {
+		return err
+	}
+	found, err := s.client.List(s.ctx, dirPath, *recursive)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]listEntry, 0, len(found))
+	for _, entry := range found {
+		entries = append(entries, listEntry{
+			Path:        entry.Path,
+			IsDir:       entry.IsDir,
+			Size:        entry.Size,
+			ModifiedAt:  formatTime(entry.ModifiedAt),
+			ContentType: entry.ContentType,
+		})
+	}
+	s.print(entries, func() {
+		for _, entry := range entries {
+			if entry.IsDir {
+				fmt.Printf("%-10s %s %s/\n", "<dir>", entry.ModifiedAt, entry.Path)
+			} else {
+				fmt.Printf("%-10d %s %s\n", entry.Size, entry.ModifiedAt, entry.Path)
+			}
+		}
+	})
+	return nil
+}
+
+type replicaStat struct {
+	DataNodeId  int32  `json:"dataNodeId"`
+	GrpcAddress string `json:"grpcAddress"`
+	Alive       bool   `json:"alive"`
+}
+
+type blockStat struct {
+	BlockId  string        `json:"blockId"`
+	Offset   int64         `json:"offset"`
+	Size     int64         `json:"size"`
+	Checksum string        `json:"checksum"`
+	Replicas []replicaStat `json:"replicas"`
+}
+
+type fileStat struct {
+	Path        string      `json:"path"`
+	Size        int64       `json:"size"`
+	ContentType string      `json:"contentType"`
+	Replication int32       `json:"replication"`
+	Checksum    string      `json:"checksum"`
+	CreatedAt   string      `json:"createdAt"`
+	ModifiedAt  string      `json:"modifiedAt"`
+	Blocks      []blockStat `json:"blocks"`
+}
+
+func stat(s *session, args []string) error {
+	flags := flag.NewFlagSet("stat", flag.ContinueOnError)
+	if err := parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	if err := checkPath(flags.Arg(0), false); err != nil {
+		return err
+	}
+	info, err := s.client.Stat(s.ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	result := fileStat{
+		Path:        info.Name,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		Replication: info.Replication,
+		Checksum:    info.Checksum,
+		CreatedAt:   formatTime(info.CreatedAt),
+		ModifiedAt:  formatTime(info.ModifiedAt),
+		Blocks:      make([]blockStat, 0, len(info.Blocks)),
+	}
+	for _, block := range info.Blocks {
+		bs := blockStat{BlockId: block.BlockId, Offset: block.Offset, Size: block.Size, Checksum: block.Checksum, Replicas: make([]replicaStat, 0)}
+		for _, replica := range block.Replicas {
+			bs.Replicas = append(bs.Replicas, replicaStat{DataNodeId: replica.DataNodeId, GrpcAddress: replica.GrpcAddress, Alive: replica.Alive})
+		}
+		result.Blocks = append(result.Blocks, bs)
+	}
+
+	s.print(result, func() {
+		fmt.Println("Path:", result.Path)
+		fmt.Println("Size:", result.Size)
+		fmt.Println("Content type:", result.ContentType)
+		fmt.Println("Replication factor:", result.Replication)
+		fmt.Println("Checksum:", result.Checksum)
+		fmt.Println("Created:", result.CreatedAt)
+		fmt.Println("Modified:", result.ModifiedAt)
+		for _, block := range result.Blocks {
+			fmt.Printf("Block %s (%d bytes at offset %d)\n", block.BlockId, block.Size, block.Offset)
+			for _, replica := range block.Replicas {
+				state := "alive"
+				if !replica.Alive {
+					state = "dead"
+				}
+				fmt.Printf("  Data Keeper %d at %s (%s)\n", replica.DataNodeId, replica.GrpcAddress, state)
+			}
+		}
+	})
+	return nil
+}
+
+type pathsResult struct {
+	Paths []string `json:"paths"`
+}
+
+func rm(s *session, args []string) error {
+	flags := flag.NewFlagSet("rm", flag.ContinueOnError)
+	dir := flags.Bool("d", false, "remove empty directories instead of files")
+	if err := parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+	for _, target := range flags.Args() {
+		if err := checkPath(target, false); err != nil {
+			return err
+		}
+		var err error
+		if *dir {
+			err = s.client.RemoveDir(s.ctx, target)
+		} else {
+			err = s.client.Remove(s.ctx, target)
+		}
+		if err != nil {
+			return fmt.Errorf("removing %s: %w", target, err)
+		}
+	}
+	s.print(pathsResult{Paths: flags.Args()}, func() {
+		for _, target := range flags.Args() {
+			fmt.Println("Removed", target)
+		}
+	})
+	return nil
+}
+
+func mkdir(s *session, args []string) error {
+	flags := flag.NewFlagSet("mkdir", flag.ContinueOnError)
+	parents := flags.Bool("p", false, "make missing parent directories too")
+	if err := parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+	for _, dirPath := range flags.Args() {
+		if err := checkPath(dirPath, false); err != nil {
+			return err
+		}
+		if err := s.client.Mkdir(s.ctx, dirPath, *parents); err != nil {
+			return fmt.Errorf("making %s: %w", dirPath, err)
+		}
+	}
+	s.print(pathsResult{Paths: flags.Args()}, func() {
+		for _, dirPath := range flags.Args() {
+			fmt.Println("Made", dirPath)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,184 @@
+// Command dfs is a scriptable client for the file system. Every operation is a subcommand, results can be
+// printed as JSON and the exit status tells callers what went wrong.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"src/dfsclient"
+	"src/masters"
+
+	"github.com/joho/godotenv"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit statuses
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNotFound    = 3
+	exitExists      = 4
+	exitUnavailable = 5
+	exitCorrupt     = 6
+)
+
+const usage = `Usage: dfs [--master addresses] [--json] [--timeout duration] <command> [flags] [arguments]
+
+Commands:
+  put [-r replication] [-type mime] <local file> <path>   upload a file ("-" reads standard input)
+  get [-f] <path> [local file]                           download a file ("-" writes standard output)
+  cat <path>                                             write a file to standard output
+  ls [-R] [path]                                         list a directory
+  stat <path>                                            show a file and where its blocks are
+  rm [-d] <path>...                                      delete files, or empty directories with -d
+  mkdir [-p] <path>...                                   make directories
+
+Exit status: 0 success, 1 error, 2 usage, 3 not found, 4 already exists,
+5 master or data keepers unavailable, 6 checksum mismatch.
+`
+
+// usageError is a mistake in the command line
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// command runs one subcommand with its own arguments
+type command func(s *session, args []string) error
+
+var commands = map[string]command{
+	"put":   put,
+	"get":   get,
+	"cat":   cat,
+	"ls":    ls,
+	"stat":  stat,
+	"rm":    rm,
+	"mkdir": mkdir,
+}
+
+// session is what every subcommand needs: the client, the output format and the context of the
+// command, which --timeout bounds
+type session struct {
+	client *dfsclient.Client
+	json   bool
+	ctx    context.Context
+}
+
+// print writes the result of a command, as JSON with --json and as text otherwise
+func (s *session) print(value interface{}, text func()) {
+	if !s.json {
+		text()
+		return
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// exitCode maps an error to the exit status of the process
+func exitCode(err error) int {
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return exitNotFound
+	case codes.AlreadyExists:
+		return exitExists
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return exitUnavailable
+	case codes.DataLoss:
+		return exitCorrupt
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return exitNotFound
+	}
+	if errors.Is(err, os.ErrExist) {
+		return exitExists
+	}
+	return exitError
+}
+
+// fail reports err on standard error and returns the exit status for it
+func fail(err error, asJSON bool) int {
+	code := exitCode(err)
+	msg := err.Error()
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		msg = s.Message()
+	}
+	if asJSON {
+		json.NewEncoder(os.Stderr).Encode(map[string]interface{}{"error": msg, "exitCode": code})
+	} else {
+		fmt.Fprintln(os.Stderr, "dfs:", msg)
+	}
+	return code
+}
+
+func run() int {
+	flags := flag.NewFlagSet("dfs", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	masterList := flags.String("master", "", "comma separated master addresses (default MASTER_ADDRESSES or MASTER_PORT)")
+	asJSON := flags.Bool("json", false, "print results as JSON")
+	timeout := flags.Duration("timeout", 0, "give up after this long (0 waits forever)")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	name, args := flags.Arg(0), flags.Args()[1:]
+	run, ok := commands[name]
+	if !ok {
+		defer flags.Usage()
+		return fail(usagef("unknown command %q", name), *asJSON)
+	}
+
+	// The .env file is optional here, as --master can name the masters
+	godotenv.Load()
+	addrs := masters.Addresses()
+	if *masterList != "" {
+		addrs = make([]string, 0)
+		for _, addr := range strings.Split(*masterList, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	if len(addrs) == 0 || addrs[0] == "" {
+		return fail(usagef("no master address: use --master or set MASTER_ADDRESSES"), *asJSON)
+	}
+
+	client := dfsclient.Dial(addrs)
+	defer client.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), *timeout)
+	}
+	defer cancel()
+	s := &session{client: client, json: *asJSON, ctx: ctx}
+	if err := run(s, args); err != nil {
+		return fail(err, *asJSON)
+	}
+	return exitOK
+}
+
+func main() {
+	os.Exit(run())
+}
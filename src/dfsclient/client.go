@@ -0,0 +1,228 @@
+// Package dfsclient reads and writes files of the distributed file system. Calls go to the leader of
+// the master group, and block transfers are retried and fail over to other replicas on their own.
+// Errors carry gRPC status codes, such as codes.NotFound for missing files and codes.DataLoss for
+// data that does not match its checksum.
+package dfsclient
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+
+	"src/masters"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// streamChunkSize is the size of the data carried by one UploadPart message
+	streamChunkSize = 1 << 20
+	// maxBlocksInFlight bounds how many blocks are uploaded or downloaded at the same time
+	maxBlocksInFlight = 4
+	// listPageSize is the number of entries fetched per ListFiles call
+	listPageSize = 500
+)
+
+// Client talks to one master group. It is safe for concurrent use.
+type Client struct {
+	// Attempts is how many times a block transfer is tried before giving up
+	Attempts int
+	// Backoff is the wait after the first failed attempt, doubled after every other one
+	Backoff time.Duration
+	// ChunkSize is the size of the ranges Download splits blocks into, 0 to stream whole blocks instead
+	ChunkSize int64
+	// Workers is the number of ranges Download fetches at the same time
+	Workers int
+
+	conn   *masters.Conn
+	master pb.MasterTrackerServiceClient
+	stats  *replicaStats
+}
+
+// Dial prepares a client for the master group at addrs. Downloads are set up from PARALLEL_DOWNLOAD,
+// DOWNLOAD_CHUNK_SIZE and DOWNLOAD_WORKERS.
+func Dial(addrs []string) *Client {
+	conn := masters.Dial(addrs)
+	return &Client{
+		Attempts:  3,
+		Backoff:   200 * time.Millisecond,
+		ChunkSize: downloadChunkSize(),
+		Workers:   downloadWorkers(),
+		conn:      conn,
+		master:    pb.NewMasterTrackerServiceClient(conn),
+		stats:     newReplicaStats(),
+	}
+}
+
+// Close releases the connections to the masters
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// retryable reports whether a failed transfer may succeed when tried again. Aborted uploads are
+// started over as a whole instead.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Internal, codes.DataLoss, codes.ResourceExhausted, codes.Unknown, codes.FailedPrecondition:
+		return true
+	}
+	return false
+}
+
+// retry calls fn until it succeeds, fails for good or runs out of attempts, sleeping between attempts
+func (c *Client) retry(ctx context.Context, fn func(attempt int) error) error {
+	var err error
+	backoff := c.Backoff
+	for attempt := 0; attempt < max(c.Attempts, 1); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		if err = fn(attempt); err == nil || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// forEachBlock runs fn for n blocks, maxBlocksInFlight at a time, and returns the first error
+func forEachBlock(n int, fn func(i int) error) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	slots := make(chan struct{}, maxBlocksInFlight)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := fn(i); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// shuffled returns the addresses in random order, so reads spread over the replicas
+func shuffled(addresses []string) []string {
+	out := make([]string, len(addresses))
+	for i, j := range rand.Perm(len(addresses)) {
+		out[i] = addresses[j]
+	}
+	return out
+}
+
+// Replica is one copy of a block
+type Replica struct {
+	DataNodeId  int32
+	GrpcAddress string
+	Alive       bool
+}
+
+// BlockInfo describes one block of a file
+type BlockInfo struct {
+	BlockId  string
+	Offset   int64
+	Size     int64
+	Checksum string // hex SHA-256
+	Replicas []Replica
+}
+
+// FileInfo describes a stored file
+type FileInfo struct {
+	Name        string
+	Size        int64
+	ContentType string
+	Replication int32 // effective replication factor
+	Checksum    string
+	CreatedAt   time.Time
+	ModifiedAt  time.Time
+	Blocks      []BlockInfo
+}
+
+// Stat describes a file and where the replicas of its blocks are
+func (c *Client) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	resp, err := c.master.StatFile(ctx, &pb.StatFileRequest{FileName: name})
+	if err != nil {
+		return nil, err
+	}
+	info := &FileInfo{
+		Name:        resp.GetFileName(),
+		Size:        resp.GetFileSize(),
+		ContentType: resp.GetContentType(),
+		Replication: resp.GetReplication(),
+		Checksum:    resp.GetChecksum(),
+		CreatedAt:   time.Unix(resp.GetCreatedAt(), 0),
+		ModifiedAt:  time.Unix(resp.GetModifiedAt(), 0),
+	}
+	for _, block := range resp.GetBlocks() {
+		blockInfo := BlockInfo{BlockId: block.GetBlockId(), Offset: block.GetOffset(), Size: block.GetSize(), Checksum: block.GetChecksum()}
+		for _, replica := range block.GetReplicas() {
+			blockInfo.Replicas = append(blockInfo.Replicas, Replica{DataNodeId: replica.GetDataNodeId(), GrpcAddress: replica.GetGrpcAddress(), Alive: replica.GetAlive()})
+		}
+		info.Blocks = append(info.Blocks, blockInfo)
+	}
+	return info, nil
+}
+
+// Remove deletes a file
+func (c *Client) Remove(ctx context.Context, name string) error {
+	_, err := c.master.DeleteFile(ctx, &pb.DeleteFileRequest{FileName: name})
+	return err
+}
+
+// Mkdir makes a directory, and its missing parents with parents set
+func (c *Client) Mkdir(ctx context.Context, dir string, parents bool) error {
+	_, err := c.master.Mkdir(ctx, &pb.MkdirRequest{Path: dir, Parents: parents})
+	return err
+}
+
+// RemoveDir removes an empty directory
+func (c *Client) RemoveDir(ctx context.Context, dir string) error {
+	_, err := c.master.Rmdir(ctx, &pb.RmdirRequest{Path: dir})
+	return err
+}
+
+// Entry is a file or directory returned by List
+type Entry struct {
+	Path        string
+	IsDir       bool
+	Size        int64
+	ModifiedAt  time.Time
+	ContentType string // empty for directories
+}
+
+// List returns the entries of a directory, and of all its subdirectories with recursive set
+func (c *Client) List(ctx context.Context, dir string, recursive bool) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	pageToken := ""
+	for {
+		resp, err := c.master.ListFiles(ctx, &pb.ListFilesRequest{Path: dir, Recursive: recursive, PageSize: listPageSize, PageToken: pageToken})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range resp.GetEntries() {
+			entries = append(entries, Entry{
+				Path:        entry.GetPath(),
+				IsDir:       entry.GetIsDir(),
+				Size:        entry.GetSize(),
+				ModifiedAt:  time.Unix(entry.GetModifiedAt(), 0),
+				ContentType: entry.GetContentType(),
+			})
+		}
+		if pageToken = resp.GetNextPageToken(); pageToken == "" {
+			return entries, nil
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package dfsclient
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	dk "src/grpc/datakeeper"
+	pb "src/grpc/master"
+
+	"src/connpool"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultChunkSize is the size of the ranges a download is split into
+	defaultChunkSize = 4 << 20
+	defaultWorkers   = 8
+	// throughputWeight is how much the latest range counts in the throughput estimate of a replica
+	throughputWeight = 0.3
+	// exploreRate is the share of ranges sent to a random replica, so the estimates of slow replicas keep updating
+	exploreRate = 0.1
+)
+
+// downloadChunkSize reads DOWNLOAD_CHUNK_SIZE, the bytes per range of a parallel download.
+// PARALLEL_DOWNLOAD=false streams whole blocks instead.
+func downloadChunkSize() int64 {
+	if os.Getenv("PARALLEL_DOWNLOAD") == "false" {
+		return 0
+	}
+	size, err := strconv.ParseInt(os.Getenv("DOWNLOAD_CHUNK_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		return defaultChunkSize
+	}
+	return size
+}
+
+// downloadWorkers reads DOWNLOAD_WORKERS, the number of ranges downloaded at the same time
+func downloadWorkers() int {
+	workers, err := strconv.Atoi(os.Getenv("DOWNLOAD_WORKERS"))
+	if err != nil || workers <= 0 {
+		return defaultWorkers
+	}
+	return workers
+}
+
+// replicaStats estimates the throughput of every data keeper from the ranges it served
+type replicaStats struct {
+	mu         sync.Mutex
+	throughput map[string]float64 // bytes per second
+	inFlight   map[string]int
+}
+
+func newReplicaStats() *replicaStats {
+	return &replicaStats{throughput: make(map[string]float64), inFlight: make(map[string]int)}
+}
+
+// pick chooses the replica for a range among the addresses not tried yet: replicas never measured first,
+// then the best throughput per range in flight, and now and then a random one
+func (s *replicaStats) pick(addresses []string, tried map[string]bool) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	candidates := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if !tried[address] {
+			candidates = append(candidates, address)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	if rand.Float64() < exploreRate {
+		return candidates[rand.Intn(len(candidates))], true
+	}
+	best, bestScore := "", -1.0
+	for _, i := range rand.Perm(len(candidates)) {
+		address := candidates[i]
+		throughput, measured := s.throughput[address]
+		score := math.Inf(1)
+		if measured {
+			score = throughput / float64(1+s.inFlight[address])
+		}
+		if score > bestScore {
+			best, bestScore = address, score
+		}
+	}
+	return best, true
+}
+
+func (s *replicaStats) start(address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[address]++
+}
+
+// done records a finished range. Failures halve the estimate of the replica.
+func (s *replicaStats) done(address string, bytes int64, elapsed time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[address]--
+	previous, measured := s.throughput[address]
+	if err != nil {
+		s.throughput[address] = previous / 2
+		return
+	}
+	latest := float64(bytes) / math.Max(elapsed.Seconds(), 1e-6)
+	if !measured {
+		s.throughput[address] = latest
+		return
+	}
+	s.throughput[address] = (1-throughputWeight)*previous + throughputWeight*latest
+}
+
+// chunk is a range of one block, at an offset within the block
+type chunk struct {
+	block  *pb.BlockLocation
+	index  int
+	offset int64
+	length int64
+}
+
+// downloadChunks splits the blocks of a file into ranges of ChunkSize bytes fetched by Workers goroutines,
+// and writes each range at its offset in w
+func (c *Client) downloadChunks(ctx context.Context, file *pb.DownloadFileResponse, w io.WriterAt) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var once sync.Once
+	var firstErr error
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// Every block is checked once its last range is written
+	blocks := file.GetBlocks()
+	remaining := make([]atomic.Int64, len(blocks))
+	for i, block := range blocks {
+		remaining[i].Store((block.GetSize() + c.ChunkSize - 1) / c.ChunkSize)
+	}
+
+	chunks := make(chan chunk)
+	var wg sync.WaitGroup
+	for i := 0; i < max(c.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ch := range chunks {
+				if err := c.fetchChunk(ctx, ch, w); err != nil {
+					fail(err)
+					continue
+				}
+				if remaining[ch.index].Add(-1) == 0 {
+					if err := c.verifyBlock(ctx, ch.block, w); err != nil {
+						fail(err)
+					}
+				}
+			}
+		}()
+	}
+
+produce:
+	for i, block := range blocks {
+		for offset := int64(0); offset < block.GetSize(); offset += c.ChunkSize {
+			ch := chunk{block: block, index: i, offset: offset, length: min(c.ChunkSize, block.GetSize()-offset)}
+			select {
+			case chunks <- ch:
+			case <-ctx.Done():
+				break produce
+			}
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	if firstErr == nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return firstErr
+}
+
+// fetchChunk writes one range into w. A failed range is tried on the other replicas of its block,
+// and on all of them again after a backoff.
+func (c *Client) fetchChunk(ctx context.Context, ch chunk, w io.WriterAt) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(ch.block.GetAddresses()) == 0 {
+		return status.Errorf(codes.Unavailable, "no data keeper holding block %s is available", ch.block.GetBlockId())
+	}
+	err := c.retry(ctx, func(int) error {
+		tried := make(map[string]bool)
+		var err error
+		for {
+			address, ok := c.stats.pick(ch.block.GetAddresses(), tried)
+			if !ok {
+				return err
+			}
+			tried[address] = true
+			if err = c.readRange(ctx, address, ch, w); err == nil || ctx.Err() != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("downloading %d bytes at offset %d of block %s: %w", ch.length, ch.offset, ch.block.GetBlockId(), err)
+	}
+	return nil
+}
+
+// readRange streams one range from the data keeper at address and writes every frame at its offset in w
+func (c *Client) readRange(ctx context.Context, address string, ch chunk, w io.WriterAt) error {
+	conn, release, err := connpool.Get(address)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	c.stats.start(address)
+	started := time.Now()
+	received, err := receiveRange(ctx, dk.NewDataKeeperServiceClient(conn), ch, w)
+	c.stats.done(address, received, time.Since(started), err)
+	return err
+}
+
+// receiveRange reads the frames of one range into w and returns how many bytes arrived
+func receiveRange(ctx context.Context, d dk.DataKeeperServiceClient, ch chunk, w io.WriterAt) (int64, error) {
+	stream, err := d.ReadRange(ctx, &dk.ReadRangeRequest{FileName: ch.block.GetBlockId(), Offset: ch.offset, Length: ch.length})
+	if err != nil {
+		return 0, err
+	}
+	var received int64
+	for {
+		frame, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return received, err
+		}
+		if frame.GetOffset() != ch.offset+received || crc32.Checksum(frame.GetData(), crc32cTable) != frame.GetCrc32C() {
+			return received, status.Errorf(codes.DataLoss, "corrupt frame at offset %d of block %s", frame.GetOffset(), ch.block.GetBlockId())
+		}
+		if _, err := w.WriteAt(frame.GetData(), ch.block.GetOffset()+frame.GetOffset()); err != nil {
+			return received, err
+		}
+		received += int64(len(frame.GetData()))
+	}
+	// The range stops early when the replica is shorter than the block
+	if received != ch.length {
+		return received, status.Errorf(codes.DataLoss, "received %d bytes of block %s, expected %d", received, ch.block.GetBlockId(), ch.length)
+	}
+	return received, nil
+}
+
+// verifyBlock checks a block assembled from ranges against its checksum when w can be read back.
+// A block that does not match is downloaded again as a whole, from a stream checked on the way.
+func (c *Client) verifyBlock(ctx context.Context, block *pb.BlockLocation, w io.WriterAt) error {
+	r, ok := w.(io.ReaderAt)
+	if !ok || block.GetChecksum() == "" {
+		return nil
+	}
+	sum, err := checksum(r, block.GetOffset(), block.GetSize())
+	if err != nil {
+		return err
+	}
+	if sum == block.GetChecksum() {
+		return nil
+	}
+	return c.fetchBlock(ctx, block, func() io.Writer {
+		return io.NewOffsetWriter(w, block.GetOffset())
+	})
+}
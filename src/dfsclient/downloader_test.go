@@ -0,0 +1,186 @@
+package dfsclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	dk "src/grpc/datakeeper"
+	pb "src/grpc/master"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memFile is a file of fixed size held in memory
+type memFile []byte
+
+func (f memFile) WriteAt(p []byte, off int64) (int, error) {
+	return copy(f[off:], p), nil
+}
+
+func (f memFile) ReadAt(p []byte, off int64) (int, error) {
+	n := copy(p, f[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// rangeKeeper serves ReadRange from a list of frames, then ends with end (io.EOF when nil)
+type rangeKeeper struct {
+	dk.DataKeeperServiceClient
+	frames []*dk.DownloadResponse
+	end    error
+}
+
+func (k *rangeKeeper) ReadRange(ctx context.Context, in *dk.ReadRangeRequest, opts ...grpc.CallOption) (dk.DataKeeperService_ReadRangeClient, error) {
+	return &rangeStream{keeper: k}, nil
+}
+
+type rangeStream struct {
+	grpc.ClientStream
+	keeper *rangeKeeper
+}
+
+func (s *rangeStream) Recv() (*dk.DownloadResponse, error) {
+	k := s.keeper
+	if len(k.frames) == 0 {
+		if k.end != nil {
+			return nil, k.end
+		}
+		return nil, io.EOF
+	}
+	frame := k.frames[0]
+	k.frames = k.frames[1:]
+	return frame, nil
+}
+
+func frame(offset int64, data string) *dk.DownloadResponse {
+	return &dk.DownloadResponse{Offset: offset, Data: []byte(data), Crc32C: crc32.Checksum([]byte(data), crc32cTable)}
+}
+
+func TestReceiveRange(t *testing.T) {
+	corrupt := frame(6, "cdef")
+	corrupt.Crc32C++
+	broken := status.Error(codes.Unavailable, "connection reset")
+
+	// The range covers bytes 4 to 10 of a block stored at offset 2 of the file
+	for _, c := range []struct {
+		name     string
+		frames   []*dk.DownloadResponse
+		end      error
+		code     codes.Code
+		received int64
+		file     string
+	}{
+		{"whole range", []*dk.DownloadResponse{frame(4, "ab"), frame(6, "cdef")}, nil, codes.OK, 6, "......abcdef"},
+		{"frame out of order", []*dk.DownloadResponse{frame(6, "cdef"), frame(4, "ab")}, nil, codes.DataLoss, 0, "............"},
+		{"corrupt frame", []*dk.DownloadResponse{frame(4, "ab"), corrupt}, nil, codes.DataLoss, 2, "......ab...."},
+		{"replica shorter than the block", []*dk.DownloadResponse{frame(4, "ab")}, nil, codes.DataLoss, 2, "......ab...."},
+		{"stream broken", []*dk.DownloadResponse{frame(4, "ab")}, broken, codes.Unavailable, 2, "......ab...."},
+	} {
+		file := memFile("............")
+		ch := chunk{block: &pb.BlockLocation{BlockId: "b0", Offset: 2, Size: 10}, offset: 4, length: 6}
+		received, err := receiveRange(context.Background(), &rangeKeeper{frames: c.frames, end: c.end}, ch, file)
+		if status.Code(err) != c.code || received != c.received {
+			t.Errorf("%s: received %d, %v; want %d, %v", c.name, received, err, c.received, c.code)
+		}
+		if string(file) != c.file {
+			t.Errorf("%s: wrote %q, want %q", c.name, file, c.file)
+		}
+	}
+}
+
+func TestReplicaStatsPick(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		throughput map[string]float64
+		inFlight   map[string]int
+		tried      map[string]bool
+		want       string
+	}{
+		{"unmeasured replica first", map[string]float64{"a": 100}, nil, nil, "b"},
+		{"fastest replica", map[string]float64{"a": 100, "b": 50}, nil, nil, "a"},
+		{"ranges in flight share the throughput", map[string]float64{"a": 100, "b": 50}, map[string]int{"a": 2}, nil, "b"},
+		{"replicas tried are skipped", map[string]float64{"a": 100, "b": 50}, nil, map[string]bool{"a": true}, "b"},
+	} {
+		s := newReplicaStats()
+		for address, throughput := range c.throughput {
+			s.throughput[address] = throughput
+		}
+		for address, n := range c.inFlight {
+			s.inFlight[address] = n
+		}
+		// Some picks explore a random replica, so most of them have to go to the best one
+		picked := 0
+		for i := 0; i < 1000; i++ {
+			if address, _ := s.pick([]string{"a", "b"}, c.tried); address == c.want {
+				picked++
+			}
+		}
+		if picked < 800 {
+			t.Errorf("%s: picked %s %d times out of 1000", c.name, c.want, picked)
+		}
+	}
+
+	if address, ok := newReplicaStats().pick([]string{"a"}, map[string]bool{"a": true}); ok {
+		t.Errorf("picked %s once every replica was tried", address)
+	}
+}
+
+func TestReplicaStatsDone(t *testing.T) {
+	s := newReplicaStats()
+	for _, c := range []struct {
+		name  string
+		bytes int64
+		err   error
+		want  float64
+	}{
+		{"first range sets the estimate", 100, nil, 100},
+		{"next ranges are averaged", 200, nil, 0.7*100 + 0.3*200},
+		{"a failure halves it", 0, errors.New("reset"), (0.7*100 + 0.3*200) / 2},
+	} {
+		s.start("a")
+		s.done("a", c.bytes, time.Second, c.err)
+		if got := s.throughput["a"]; math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s: throughput %v, want %v", c.name, got, c.want)
+		}
+	}
+	if s.inFlight["a"] != 0 {
+		t.Errorf("%d ranges still in flight", s.inFlight["a"])
+	}
+}
+
+// writeOnly hides the ReadAt method of a file
+type writeOnly struct{ io.WriterAt }
+
+func TestVerifyBlock(t *testing.T) {
+	file := memFile("..abcdef..")
+	sum := sha256.Sum256([]byte("abcdef"))
+	block := &pb.BlockLocation{BlockId: "b0", Offset: 2, Size: 6, Checksum: hex.EncodeToString(sum[:])}
+	unchecked := &pb.BlockLocation{BlockId: "b0", Offset: 2, Size: 6}
+
+	// None of these fetch the block again, so the client needs no master
+	c := &Client{stats: newReplicaStats()}
+	for _, tc := range []struct {
+		name  string
+		block *pb.BlockLocation
+		w     io.WriterAt
+	}{
+		{"block matching its checksum", block, file},
+		{"block without a checksum", unchecked, file},
+		{"output that cannot be read back", block, writeOnly{memFile("..xxxxxx..")}},
+	} {
+		if err := c.verifyBlock(context.Background(), tc.block, tc.w); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package dfsclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+
+	dk "src/grpc/datakeeper"
+	pb "src/grpc/master"
+
+	"src/connpool"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// locate asks the master where the blocks of a file are
+func (c *Client) locate(ctx context.Context, name string) (*pb.DownloadFileResponse, error) {
+	file, err := c.master.DownloadFile(ctx, &pb.DownloadFileRequest{FileName: name})
+	if err != nil {
+		return nil, err
+	}
+	// The master answers with an empty response for unknown files
+	if file.GetFileName() == "" {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", name)
+	}
+	return file, nil
+}
+
+// readBlock streams one block from the data keeper at address into w and checks it on the way
+func readBlock(ctx context.Context, address string, block *pb.BlockLocation, w io.Writer) error {
+	conn, release, err := connpool.Get(address)
+	if err != nil {
+		return err
+	}
+	defer release()
+	stream, err := dk.NewDataKeeperServiceClient(conn).Download(ctx, &dk.DownloadRequest{FileName: block.GetBlockId()})
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	var offset int64
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.GetOffset() != offset || crc32.Checksum(chunk.GetData(), crc32cTable) != chunk.GetCrc32C() {
+			return status.Errorf(codes.DataLoss, "corrupt chunk at offset %d", chunk.GetOffset())
+		}
+		hash.Write(chunk.GetData())
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return err
+		}
+		offset += int64(len(chunk.GetData()))
+	}
+	if offset != block.GetSize() {
+		return status.Errorf(codes.DataLoss, "received %d bytes, expected %d", offset, block.GetSize())
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); block.GetChecksum() != "" && sum != block.GetChecksum() {
+		return status.Errorf(codes.DataLoss, "checksum mismatch: got %s, expected %s", sum, block.GetChecksum())
+	}
+	return nil
+}
+
+// fetchBlock reads a block from its replicas in random order, going over them again after a backoff
+// until one returns the block intact. target is called before every attempt and returns where the
+// block is written.
+func (c *Client) fetchBlock(ctx context.Context, block *pb.BlockLocation, target func() io.Writer) error {
+	if len(block.GetAddresses()) == 0 {
+		return status.Errorf(codes.Unavailable, "no data keeper holding block %s is available", block.GetBlockId())
+	}
+	err := c.retry(ctx, func(int) error {
+		var err error
+		for _, address := range shuffled(block.GetAddresses()) {
+			if err = readBlock(ctx, address, block, target()); err == nil || ctx.Err() != nil {
+				return err
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("downloading block %s: %w", block.GetBlockId(), err)
+	}
+	return nil
+}
+
+// Download writes a whole file to w, each part at its offset. Blocks are split into ranges of ChunkSize
+// bytes fetched in parallel from the replicas that served fastest so far, or streamed whole, several at
+// a time, when ChunkSize is 0. Every block is checked against its checksum; ranges only when w is also
+// an io.ReaderAt, such as an *os.File.
+func (c *Client) Download(ctx context.Context, name string, w io.WriterAt) error {
+	file, err := c.locate(ctx, name)
+	if err != nil {
+		return err
+	}
+	if c.ChunkSize > 0 {
+		return c.downloadChunks(ctx, file, w)
+	}
+	blocks := file.GetBlocks()
+	return forEachBlock(len(blocks), func(i int) error {
+		return c.fetchBlock(ctx, blocks[i], func() io.Writer {
+			return io.NewOffsetWriter(w, blocks[i].GetOffset())
+		})
+	})
+}
+
+// reader reads a file one block at a time. The current block is kept in memory, so reads within it
+// and seeks back to it do not go to the data keepers again.
+type reader struct {
+	c      *Client
+	ctx    context.Context
+	file   *pb.DownloadFileResponse
+	offset int64
+	block  int // index of the block in data, -1 when none
+	data   bytes.Buffer
+	closed bool
+
+	// While the file is read from start to end, the whole file is checked against its checksum too
+	hash       hash.Hash
+	sequential bool
+}
+
+// Open opens a file for reading. ctx bounds every read.
+func (c *Client) Open(ctx context.Context, name string) (io.ReadSeekCloser, error) {
+	file, err := c.locate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{c: c, ctx: ctx, file: file, block: -1, hash: sha256.New(), sequential: true}, nil
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.offset >= r.file.GetFileSize() {
+		if r.sequential && r.file.GetChecksum() != "" {
+			r.sequential = false
+			if sum := hex.EncodeToString(r.hash.Sum(nil)); sum != r.file.GetChecksum() {
+				return 0, status.Errorf(codes.DataLoss, "checksum %s, expected %s", sum, r.file.GetChecksum())
+			}
+		}
+		return 0, io.EOF
+	}
+
+	blocks := r.file.GetBlocks()
+	i := sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].GetOffset()+blocks[i].GetSize() > r.offset
+	})
+	if i != r.block {
+		r.block = -1
+		err := r.c.fetchBlock(r.ctx, blocks[i], func() io.Writer {
+			r.data.Reset()
+			return &r.data
+		})
+		if err != nil {
+			return 0, err
+		}
+		r.block = i
+	}
+	n := copy(p, r.data.Bytes()[r.offset-blocks[i].GetOffset():])
+	if r.sequential {
+		r.hash.Write(p[:n])
+	}
+	r.offset += int64(n)
+	return n, nil
+}
+
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.file.GetFileSize()
+	default:
+		return 0, errors.New("dfsclient: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("dfsclient: negative position")
+	}
+	if offset != r.offset {
+		r.sequential = false
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *reader) Close() error {
+	if r.closed {
+		return os.ErrClosed
+	}
+	r.closed = true
+	r.data = bytes.Buffer{}
+	return nil
+}
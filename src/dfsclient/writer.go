@@ -0,0 +1,258 @@
+package dfsclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+
+	dk "src/grpc/datakeeper"
+	pb "src/grpc/master"
+
+	"src/connpool"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// CreateOptions are the settings of a new file
+type CreateOptions struct {
+	// Replication is the number of copies to keep, 0 to follow the directory
+	Replication int32
+	// ContentType is the MIME type, guessed from the name or the first bytes when empty
+	ContentType string
+	// ClientPort is where the master calls back once the file is stored, empty for no call
+	ClientPort string
+}
+
+// checksum returns the hex SHA-256 of size bytes of r starting at offset
+func checksum(r io.ReaderAt, offset int64, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(r, offset, size)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// detectContentType guesses the MIME type of a file from the extension of its name,
+// and from its first bytes when the extension is unknown
+func detectContentType(name string, r io.ReaderAt) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(io.NewSectionReader(r, 0, int64(len(head))), head)
+	return http.DetectContentType(head[:n])
+}
+
+// Upload stores size bytes read from r as a new file. It returns once every block is committed,
+// which is when the file becomes visible. A failed block resumes from the bytes its data keeper
+// already staged, or moves to another data keeper when its own cannot be reached. An upload whose
+// session the master lost, as after a failover, starts over.
+func (c *Client) Upload(ctx context.Context, name string, r io.ReaderAt, size int64, opts CreateOptions) error {
+	sum, err := checksum(r, 0, size)
+	if err != nil {
+		return err
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = detectContentType(name, r)
+	}
+	for attempt := 0; attempt < max(c.Attempts, 1); attempt++ {
+		if err = c.upload(ctx, name, r, size, sum, opts); status.Code(err) != codes.Aborted {
+			return err
+		}
+	}
+	return err
+}
+
+// upload sends the file through one upload session
+func (c *Client) upload(ctx context.Context, name string, r io.ReaderAt, size int64, sum string, opts CreateOptions) error {
+	// Without a client port the master does not call back: the last commit only returns once the file is stored
+	resp, err := c.master.UploadFile(ctx, &pb.UploadFileRequest{
+		FileName:    name,
+		FileSize:    size,
+		Checksum:    sum,
+		ContentType: opts.ContentType,
+		Replication: opts.Replication,
+		ClientPort:  opts.ClientPort,
+	})
+	if err != nil {
+		return err
+	}
+	blocks := resp.GetBlocks()
+	return forEachBlock(len(blocks), func(i int) error {
+		block := blocks[i]
+		// The data keeper refuses the block unless what it stored matches this checksum
+		blockSum, err := checksum(r, block.GetOffset(), block.GetSize())
+		if err != nil {
+			return err
+		}
+		var unreachable []string
+		err = c.retry(ctx, func(int) error {
+			err := uploadBlock(ctx, r, block, blockSum, resp.GetSessionId())
+			// A block stored but not registered is committed again on the same data keeper
+			if status.Code(err) != codes.Unavailable || notRegistered(err) {
+				return err
+			}
+			// The next attempt goes to another data keeper, if the master finds one
+			unreachable = append(unreachable, block.GetGrpcAddress())
+			moved, placeErr := c.master.PlaceBlock(ctx, &pb.PlaceBlockRequest{
+				SessionId:         resp.GetSessionId(),
+				BlockId:           block.GetBlockId(),
+				ExcludedAddresses: unreachable,
+			})
+			if placeErr == nil {
+				block = moved
+			}
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("uploading block %s: %w", block.GetBlockId(), err)
+		}
+		return nil
+	})
+}
+
+// notRegistered reports whether err comes from a data keeper that stored a block but could not register it
+func notRegistered(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if _, ok := detail.(*dk.NotRegistered); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadBlock stages one block on the data keeper chosen by the master and commits it. The upload starts
+// after the bytes the data keeper already staged for the session, so a retry only sends what is missing.
+func uploadBlock(ctx context.Context, r io.ReaderAt, block *pb.BlockPlacement, blockSum string, sessionId string) error {
+	conn, release, err := connpool.Get(block.GetGrpcAddress())
+	if err != nil {
+		return err
+	}
+	defer release()
+	d := dk.NewDataKeeperServiceClient(conn)
+
+	staged, err := d.UploadStatus(ctx, &dk.UploadStatusRequest{SessionId: sessionId, FileName: block.GetBlockId()})
+	if err != nil {
+		return err
+	}
+	// An empty block still needs an empty part, so there is something to commit
+	if offset := staged.GetCommittedOffset(); offset < block.GetSize() || block.GetSize() == 0 {
+		if err := uploadPart(ctx, d, r, block, sessionId, offset); err != nil {
+			return err
+		}
+	}
+
+	// The data keeper only stores and registers the block if the staged bytes match this size and checksum
+	resp, err := d.CommitUpload(ctx, &dk.CommitUploadRequest{
+		SessionId: sessionId,
+		FileName:  block.GetBlockId(),
+		Size:      block.GetSize(),
+		Sha256:    blockSum,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetSize() != block.GetSize() || resp.GetSha256() != blockSum {
+		return status.Errorf(codes.DataLoss, "block %s stored with %d bytes and checksum %s, expected %d bytes and %s",
+			block.GetBlockId(), resp.GetSize(), resp.GetSha256(), block.GetSize(), blockSum)
+	}
+	return nil
+}
+
+// uploadPart streams the block from offset to its end
+func uploadPart(ctx context.Context, d dk.DataKeeperServiceClient, r io.ReaderAt, block *pb.BlockPlacement, sessionId string, offset int64) error {
+	stream, err := d.UploadPart(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The first chunk names the block and the upload session
+	reader := io.NewSectionReader(r, block.GetOffset()+offset, block.GetSize()-offset)
+	buf := make([]byte, streamChunkSize)
+	first := true
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		if n > 0 || first {
+			chunk := &dk.UploadRequest{Offset: offset, Data: buf[:n], Crc32C: crc32.Checksum(buf[:n], crc32cTable)}
+			if first {
+				chunk.FileName = block.GetBlockId()
+				chunk.SessionId = sessionId
+				first = false
+			}
+			if err := stream.Send(chunk); err != nil {
+				break // the real error is returned by CloseAndRecv
+			}
+			offset += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			stream.CloseSend()
+			return readErr
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if resp.GetCommittedOffset() != block.GetSize() {
+		return status.Errorf(codes.FailedPrecondition, "%d bytes of block %s staged, expected %d", resp.GetCommittedOffset(), block.GetBlockId(), block.GetSize())
+	}
+	return nil
+}
+
+// writer spools a new file to a temporary file, as the master needs its size and checksum before any block is sent
+type writer struct {
+	c      *Client
+	ctx    context.Context
+	name   string
+	opts   CreateOptions
+	file   *os.File
+	closed bool
+}
+
+// Create starts a new file. What is written is uploaded by Close, which returns the upload error.
+func (c *Client) Create(ctx context.Context, name string) (io.WriteCloser, error) {
+	return c.CreateWithOptions(ctx, name, CreateOptions{})
+}
+
+// CreateWithOptions is Create with a replication factor or content type
+func (c *Client) CreateWithOptions(ctx context.Context, name string, opts CreateOptions) (io.WriteCloser, error) {
+	file, err := os.CreateTemp("", "dfsclient-*")
+	if err != nil {
+		return nil, err
+	}
+	return &writer{c: c, ctx: ctx, name: name, opts: opts, file: file}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	return w.file.Write(p)
+}
+
+func (w *writer) Close() error {
+	if w.closed {
+		return os.ErrClosed
+	}
+	w.closed = true
+	defer os.Remove(w.file.Name())
+	defer w.file.Close()
+	info, err := w.file.Stat()
+	if err != nil {
+		return err
+	}
+	return w.c.Upload(w.ctx, w.name, w.file, info.Size(), w.opts)
+}
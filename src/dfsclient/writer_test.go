@@ -0,0 +1,31 @@
+package dfsclient
+
+import (
+	"errors"
+	"testing"
+
+	dk "src/grpc/datakeeper"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNotRegistered(t *testing.T) {
+	stored, err := status.New(codes.Unavailable, "registering file: master down").WithDetails(&dk.NotRegistered{FileName: "blk_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"stored but not registered", stored.Err(), true},
+		{"data keeper unreachable", status.Error(codes.Unavailable, "connection refused"), false},
+		{"other error", errors.New("disk full"), false},
+	} {
+		if got := notRegistered(c.err); got != c.want {
+			t.Errorf("%s: notRegistered %v, want %v", c.name, got, c.want)
+		}
+	}
+}
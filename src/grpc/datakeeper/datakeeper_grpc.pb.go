@@ -22,10 +22,25 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DataKeeperServiceClient interface {
-	TransferFile(ctx context.Context, in *FilePortRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
 	ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
 	CheckFileExists(ctx context.Context, in *CheckFileExistsRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// DownloadChunk returns a range in a single message, so it is limited to the gRPC message size. ReadRange streams it.
 	DownloadChunk(ctx context.Context, in *DownloadChunkRequest, opts ...grpc.CallOption) (*DownloadChunkResponse, error)
+	// Upload stores a file sent whole in one stream, as data keepers do when they copy blocks
+	Upload(ctx context.Context, opts ...grpc.CallOption) (DataKeeperService_UploadClient, error)
+	// UploadPart stages part of a block under an upload session. The first message names the block and
+	// the session and must start at the committed offset; the bytes received stay staged if the stream breaks.
+	UploadPart(ctx context.Context, opts ...grpc.CallOption) (DataKeeperService_UploadPartClient, error)
+	// UploadStatus reports the committed offset of a block, where an interrupted upload resumes
+	UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadPartResponse, error)
+	// CommitUpload checks a staged block against its size and checksum, stores it and registers it with the master
+	CommitUpload(ctx context.Context, in *CommitUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error)
+	// Download streams a stored file
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (DataKeeperService_DownloadClient, error)
+	// ReadRange streams a range of a stored file in frames whose offsets are positions in the file
+	ReadRange(ctx context.Context, in *ReadRangeRequest, opts ...grpc.CallOption) (DataKeeperService_ReadRangeClient, error)
+	// DeleteFile removes a stored file
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
 }
 
 type dataKeeperServiceClient struct {
@@ -36,15 +51,6 @@ func NewDataKeeperServiceClient(cc grpc.ClientConnInterface) DataKeeperServiceCl
 	return &dataKeeperServiceClient{cc}
 }
 
-func (c *dataKeeperServiceClient) TransferFile(ctx context.Context, in *FilePortRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
-	out := new(SuccessResponse)
-	err := c.cc.Invoke(ctx, "/datakeeper.DataKeeperService/TransferFile", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
 func (c *dataKeeperServiceClient) ReplicateFile(ctx context.Context, in *ReplicateFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
 	out := new(SuccessResponse)
 	err := c.cc.Invoke(ctx, "/datakeeper.DataKeeperService/ReplicateFile", in, out, opts...)
@@ -72,14 +78,188 @@ func (c *dataKeeperServiceClient) DownloadChunk(ctx context.Context, in *Downloa
 	return out, nil
 }
 
+func (c *dataKeeperServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (DataKeeperService_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataKeeperService_ServiceDesc.Streams[0], "/datakeeper.DataKeeperService/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataKeeperServiceUploadClient{stream}
+	return x, nil
+}
+
+type DataKeeperService_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*UploadResponse, error)
+	grpc.ClientStream
+}
+
+type dataKeeperServiceUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataKeeperServiceUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataKeeperServiceUploadClient) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataKeeperServiceClient) UploadPart(ctx context.Context, opts ...grpc.CallOption) (DataKeeperService_UploadPartClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataKeeperService_ServiceDesc.Streams[1], "/datakeeper.DataKeeperService/UploadPart", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataKeeperServiceUploadPartClient{stream}
+	return x, nil
+}
+
+type DataKeeperService_UploadPartClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*UploadPartResponse, error)
+	grpc.ClientStream
+}
+
+type dataKeeperServiceUploadPartClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataKeeperServiceUploadPartClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataKeeperServiceUploadPartClient) CloseAndRecv() (*UploadPartResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadPartResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataKeeperServiceClient) UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadPartResponse, error) {
+	out := new(UploadPartResponse)
+	err := c.cc.Invoke(ctx, "/datakeeper.DataKeeperService/UploadStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataKeeperServiceClient) CommitUpload(ctx context.Context, in *CommitUploadRequest, opts ...grpc.CallOption) (*UploadResponse, error) {
+	out := new(UploadResponse)
+	err := c.cc.Invoke(ctx, "/datakeeper.DataKeeperService/CommitUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataKeeperServiceClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (DataKeeperService_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataKeeperService_ServiceDesc.Streams[2], "/datakeeper.DataKeeperService/Download", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataKeeperServiceDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataKeeperService_DownloadClient interface {
+	Recv() (*DownloadResponse, error)
+	grpc.ClientStream
+}
+
+type dataKeeperServiceDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataKeeperServiceDownloadClient) Recv() (*DownloadResponse, error) {
+	m := new(DownloadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataKeeperServiceClient) ReadRange(ctx context.Context, in *ReadRangeRequest, opts ...grpc.CallOption) (DataKeeperService_ReadRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataKeeperService_ServiceDesc.Streams[3], "/datakeeper.DataKeeperService/ReadRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataKeeperServiceReadRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataKeeperService_ReadRangeClient interface {
+	Recv() (*DownloadResponse, error)
+	grpc.ClientStream
+}
+
+type dataKeeperServiceReadRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataKeeperServiceReadRangeClient) Recv() (*DownloadResponse, error) {
+	m := new(DownloadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataKeeperServiceClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/datakeeper.DataKeeperService/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DataKeeperServiceServer is the server API for DataKeeperService service.
 // All implementations must embed UnimplementedDataKeeperServiceServer
 // for forward compatibility
 type DataKeeperServiceServer interface {
-	TransferFile(context.Context, *FilePortRequest) (*SuccessResponse, error)
 	ReplicateFile(context.Context, *ReplicateFileRequest) (*SuccessResponse, error)
 	CheckFileExists(context.Context, *CheckFileExistsRequest) (*SuccessResponse, error)
+	// DownloadChunk returns a range in a single message, so it is limited to the gRPC message size. ReadRange streams it.
 	DownloadChunk(context.Context, *DownloadChunkRequest) (*DownloadChunkResponse, error)
+	// Upload stores a file sent whole in one stream, as data keepers do when they copy blocks
+	Upload(DataKeeperService_UploadServer) error
+	// UploadPart stages part of a block under an upload session. The first message names the block and
+	// the session and must start at the committed offset; the bytes received stay staged if the stream breaks.
+	UploadPart(DataKeeperService_UploadPartServer) error
+	// UploadStatus reports the committed offset of a block, where an interrupted upload resumes
+	UploadStatus(context.Context, *UploadStatusRequest) (*UploadPartResponse, error)
+	// CommitUpload checks a staged block against its size and checksum, stores it and registers it with the master
+	CommitUpload(context.Context, *CommitUploadRequest) (*UploadResponse, error)
+	// Download streams a stored file
+	Download(*DownloadRequest, DataKeeperService_DownloadServer) error
+	// ReadRange streams a range of a stored file in frames whose offsets are positions in the file
+	ReadRange(*ReadRangeRequest, DataKeeperService_ReadRangeServer) error
+	// DeleteFile removes a stored file
+	DeleteFile(context.Context, *DeleteFileRequest) (*SuccessResponse, error)
 	mustEmbedUnimplementedDataKeeperServiceServer()
 }
 
@@ -87,9 +267,6 @@ type DataKeeperServiceServer interface {
 type UnimplementedDataKeeperServiceServer struct {
 }
 
-func (UnimplementedDataKeeperServiceServer) TransferFile(context.Context, *FilePortRequest) (*SuccessResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TransferFile not implemented")
-}
 func (UnimplementedDataKeeperServiceServer) ReplicateFile(context.Context, *ReplicateFileRequest) (*SuccessResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReplicateFile not implemented")
 }
@@ -99,6 +276,27 @@ func (UnimplementedDataKeeperServiceServer) CheckFileExists(context.Context, *Ch
 func (UnimplementedDataKeeperServiceServer) DownloadChunk(context.Context, *DownloadChunkRequest) (*DownloadChunkResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DownloadChunk not implemented")
 }
+func (UnimplementedDataKeeperServiceServer) Upload(DataKeeperService_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) UploadPart(DataKeeperService_UploadPartServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadPart not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) UploadStatus(context.Context, *UploadStatusRequest) (*UploadPartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadStatus not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) CommitUpload(context.Context, *CommitUploadRequest) (*UploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitUpload not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) Download(*DownloadRequest, DataKeeperService_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) ReadRange(*ReadRangeRequest, DataKeeperService_ReadRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadRange not implemented")
+}
+func (UnimplementedDataKeeperServiceServer) DeleteFile(context.Context, *DeleteFileRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
 func (UnimplementedDataKeeperServiceServer) mustEmbedUnimplementedDataKeeperServiceServer() {}
 
 // UnsafeDataKeeperServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -112,24 +310,6 @@ func RegisterDataKeeperServiceServer(s grpc.ServiceRegistrar, srv DataKeeperServ
 	s.RegisterService(&DataKeeperService_ServiceDesc, srv)
 }
 
-func _DataKeeperService_TransferFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FilePortRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(DataKeeperServiceServer).TransferFile(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/datakeeper.DataKeeperService/TransferFile",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DataKeeperServiceServer).TransferFile(ctx, req.(*FilePortRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
 func _DataKeeperService_ReplicateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReplicateFileRequest)
 	if err := dec(in); err != nil {
@@ -184,116 +364,207 @@ func _DataKeeperService_DownloadChunk_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
-// DataKeeperService_ServiceDesc is the grpc.ServiceDesc for DataKeeperService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var DataKeeperService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "datakeeper.DataKeeperService",
-	HandlerType: (*DataKeeperServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "TransferFile",
-			Handler:    _DataKeeperService_TransferFile_Handler,
-		},
-		{
-			MethodName: "ReplicateFile",
-			Handler:    _DataKeeperService_ReplicateFile_Handler,
-		},
-		{
-			MethodName: "CheckFileExists",
-			Handler:    _DataKeeperService_CheckFileExists_Handler,
-		},
-		{
-			MethodName: "DownloadChunk",
-			Handler:    _DataKeeperService_DownloadChunk_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "src/grpc/datakeeper/datakeeper.proto",
+func _DataKeeperService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataKeeperServiceServer).Upload(&dataKeeperServiceUploadServer{stream})
 }
 
-// FileSaveServiceClient is the client API for FileSaveService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type FileSaveServiceClient interface {
-	SaveFile(ctx context.Context, in *FileSaveRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+type DataKeeperService_UploadServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
 }
 
-type fileSaveServiceClient struct {
-	cc grpc.ClientConnInterface
+type dataKeeperServiceUploadServer struct {
+	grpc.ServerStream
 }
 
-func NewFileSaveServiceClient(cc grpc.ClientConnInterface) FileSaveServiceClient {
-	return &fileSaveServiceClient{cc}
+func (x *dataKeeperServiceUploadServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *fileSaveServiceClient) SaveFile(ctx context.Context, in *FileSaveRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
-	out := new(SuccessResponse)
-	err := c.cc.Invoke(ctx, "/datakeeper.FileSaveService/SaveFile", in, out, opts...)
-	if err != nil {
+func (x *dataKeeperServiceUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-// FileSaveServiceServer is the server API for FileSaveService service.
-// All implementations must embed UnimplementedFileSaveServiceServer
-// for forward compatibility
-type FileSaveServiceServer interface {
-	SaveFile(context.Context, *FileSaveRequest) (*SuccessResponse, error)
-	mustEmbedUnimplementedFileSaveServiceServer()
+func _DataKeeperService_UploadPart_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataKeeperServiceServer).UploadPart(&dataKeeperServiceUploadPartServer{stream})
 }
 
-// UnimplementedFileSaveServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedFileSaveServiceServer struct {
+type DataKeeperService_UploadPartServer interface {
+	SendAndClose(*UploadPartResponse) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
 }
 
-func (UnimplementedFileSaveServiceServer) SaveFile(context.Context, *FileSaveRequest) (*SuccessResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SaveFile not implemented")
+type dataKeeperServiceUploadPartServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedFileSaveServiceServer) mustEmbedUnimplementedFileSaveServiceServer() {}
 
-// UnsafeFileSaveServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to FileSaveServiceServer will
-// result in compilation errors.
-type UnsafeFileSaveServiceServer interface {
-	mustEmbedUnimplementedFileSaveServiceServer()
+func (x *dataKeeperServiceUploadPartServer) SendAndClose(m *UploadPartResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *dataKeeperServiceUploadPartServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DataKeeperService_UploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataKeeperServiceServer).UploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/datakeeper.DataKeeperService/UploadStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataKeeperServiceServer).UploadStatus(ctx, req.(*UploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataKeeperService_CommitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataKeeperServiceServer).CommitUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/datakeeper.DataKeeperService/CommitUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataKeeperServiceServer).CommitUpload(ctx, req.(*CommitUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataKeeperService_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataKeeperServiceServer).Download(m, &dataKeeperServiceDownloadServer{stream})
+}
+
+type DataKeeperService_DownloadServer interface {
+	Send(*DownloadResponse) error
+	grpc.ServerStream
+}
+
+type dataKeeperServiceDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataKeeperServiceDownloadServer) Send(m *DownloadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DataKeeperService_ReadRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataKeeperServiceServer).ReadRange(m, &dataKeeperServiceReadRangeServer{stream})
+}
+
+type DataKeeperService_ReadRangeServer interface {
+	Send(*DownloadResponse) error
+	grpc.ServerStream
 }
 
-func RegisterFileSaveServiceServer(s grpc.ServiceRegistrar, srv FileSaveServiceServer) {
-	s.RegisterService(&FileSaveService_ServiceDesc, srv)
+type dataKeeperServiceReadRangeServer struct {
+	grpc.ServerStream
 }
 
-func _FileSaveService_SaveFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FileSaveRequest)
+func (x *dataKeeperServiceReadRangeServer) Send(m *DownloadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DataKeeperService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileSaveServiceServer).SaveFile(ctx, in)
+		return srv.(DataKeeperServiceServer).DeleteFile(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/datakeeper.FileSaveService/SaveFile",
+		FullMethod: "/datakeeper.DataKeeperService/DeleteFile",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileSaveServiceServer).SaveFile(ctx, req.(*FileSaveRequest))
+		return srv.(DataKeeperServiceServer).DeleteFile(ctx, req.(*DeleteFileRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// FileSaveService_ServiceDesc is the grpc.ServiceDesc for FileSaveService service.
+// DataKeeperService_ServiceDesc is the grpc.ServiceDesc for DataKeeperService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
-var FileSaveService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "datakeeper.FileSaveService",
-	HandlerType: (*FileSaveServiceServer)(nil),
+var DataKeeperService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "datakeeper.DataKeeperService",
+	HandlerType: (*DataKeeperServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "SaveFile",
-			Handler:    _FileSaveService_SaveFile_Handler,
+			MethodName: "ReplicateFile",
+			Handler:    _DataKeeperService_ReplicateFile_Handler,
+		},
+		{
+			MethodName: "CheckFileExists",
+			Handler:    _DataKeeperService_CheckFileExists_Handler,
+		},
+		{
+			MethodName: "DownloadChunk",
+			Handler:    _DataKeeperService_DownloadChunk_Handler,
+		},
+		{
+			MethodName: "UploadStatus",
+			Handler:    _DataKeeperService_UploadStatus_Handler,
+		},
+		{
+			MethodName: "CommitUpload",
+			Handler:    _DataKeeperService_CommitUpload_Handler,
+		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _DataKeeperService_DeleteFile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _DataKeeperService_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadPart",
+			Handler:       _DataKeeperService_UploadPart_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _DataKeeperService_Download_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadRange",
+			Handler:       _DataKeeperService_ReadRange_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "src/grpc/datakeeper/datakeeper.proto",
 }
@@ -26,12 +26,34 @@ type MasterTrackerServiceClient interface {
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	// Upload file service for DataNode
 	UploadFile(ctx context.Context, in *UploadFileRequest, opts ...grpc.CallOption) (*UploadFileResponse, error)
+	// Moves a block of an upload to another data keeper when the client cannot reach the one chosen
+	PlaceBlock(ctx context.Context, in *PlaceBlockRequest, opts ...grpc.CallOption) (*BlockPlacement, error)
 	// Register service for DataNode
 	RegisterFile(ctx context.Context, in *RegisterFileRequest, opts ...grpc.CallOption) (*RegisterFileResponse, error)
 	// Download file service for Client
 	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (*DownloadFileResponse, error)
 	// Join service for DataNode
 	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// Scrubber report of a block replica that no longer matches its checksum
+	ReportCorruptBlock(ctx context.Context, in *CorruptBlockRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// Delete file service for Client
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// Rename file service for Client
+	RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// Stat file service for Client
+	StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*StatFileResponse, error)
+	// Directory services for Client
+	Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	Rmdir(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	// Replication factor of a file or directory
+	SetReplication(ctx context.Context, in *SetReplicationRequest, opts ...grpc.CallOption) (*SuccessResponse, error)
+	// Admin service to retire a data keeper. Calling it again reports the progress of the drain.
+	DecommissionNode(ctx context.Context, in *DecommissionNodeRequest, opts ...grpc.CallOption) (*DecommissionNodeResponse, error)
+	// Admin service to move replicas from the fullest data keepers to the emptiest ones
+	Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error)
+	// Admin service showing the state of the data nodes and how it changed
+	NodeHistory(ctx context.Context, in *NodeHistoryRequest, opts ...grpc.CallOption) (*NodeHistoryResponse, error)
 }
 
 type masterTrackerServiceClient struct {
@@ -60,6 +82,15 @@ func (c *masterTrackerServiceClient) UploadFile(ctx context.Context, in *UploadF
 	return out, nil
 }
 
+func (c *masterTrackerServiceClient) PlaceBlock(ctx context.Context, in *PlaceBlockRequest, opts ...grpc.CallOption) (*BlockPlacement, error) {
+	out := new(BlockPlacement)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/PlaceBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *masterTrackerServiceClient) RegisterFile(ctx context.Context, in *RegisterFileRequest, opts ...grpc.CallOption) (*RegisterFileResponse, error) {
 	out := new(RegisterFileResponse)
 	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/RegisterFile", in, out, opts...)
@@ -87,6 +118,105 @@ func (c *masterTrackerServiceClient) Join(ctx context.Context, in *JoinRequest,
 	return out, nil
 }
 
+func (c *masterTrackerServiceClient) ReportCorruptBlock(ctx context.Context, in *CorruptBlockRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/ReportCorruptBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/RenameFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*StatFileResponse, error) {
+	out := new(StatFileResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/StatFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/Mkdir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) Rmdir(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/Rmdir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/ListFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) SetReplication(ctx context.Context, in *SetReplicationRequest, opts ...grpc.CallOption) (*SuccessResponse, error) {
+	out := new(SuccessResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/SetReplication", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) DecommissionNode(ctx context.Context, in *DecommissionNodeRequest, opts ...grpc.CallOption) (*DecommissionNodeResponse, error) {
+	out := new(DecommissionNodeResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/DecommissionNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/Rebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *masterTrackerServiceClient) NodeHistory(ctx context.Context, in *NodeHistoryRequest, opts ...grpc.CallOption) (*NodeHistoryResponse, error) {
+	out := new(NodeHistoryResponse)
+	err := c.cc.Invoke(ctx, "/master.MasterTrackerService/NodeHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MasterTrackerServiceServer is the server API for MasterTrackerService service.
 // All implementations must embed UnimplementedMasterTrackerServiceServer
 // for forward compatibility
@@ -95,12 +225,34 @@ type MasterTrackerServiceServer interface {
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	// Upload file service for DataNode
 	UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error)
+	// Moves a block of an upload to another data keeper when the client cannot reach the one chosen
+	PlaceBlock(context.Context, *PlaceBlockRequest) (*BlockPlacement, error)
 	// Register service for DataNode
 	RegisterFile(context.Context, *RegisterFileRequest) (*RegisterFileResponse, error)
 	// Download file service for Client
 	DownloadFile(context.Context, *DownloadFileRequest) (*DownloadFileResponse, error)
 	// Join service for DataNode
 	Join(context.Context, *JoinRequest) (*SuccessResponse, error)
+	// Scrubber report of a block replica that no longer matches its checksum
+	ReportCorruptBlock(context.Context, *CorruptBlockRequest) (*SuccessResponse, error)
+	// Delete file service for Client
+	DeleteFile(context.Context, *DeleteFileRequest) (*SuccessResponse, error)
+	// Rename file service for Client
+	RenameFile(context.Context, *RenameFileRequest) (*SuccessResponse, error)
+	// Stat file service for Client
+	StatFile(context.Context, *StatFileRequest) (*StatFileResponse, error)
+	// Directory services for Client
+	Mkdir(context.Context, *MkdirRequest) (*SuccessResponse, error)
+	Rmdir(context.Context, *RmdirRequest) (*SuccessResponse, error)
+	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	// Replication factor of a file or directory
+	SetReplication(context.Context, *SetReplicationRequest) (*SuccessResponse, error)
+	// Admin service to retire a data keeper. Calling it again reports the progress of the drain.
+	DecommissionNode(context.Context, *DecommissionNodeRequest) (*DecommissionNodeResponse, error)
+	// Admin service to move replicas from the fullest data keepers to the emptiest ones
+	Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error)
+	// Admin service showing the state of the data nodes and how it changed
+	NodeHistory(context.Context, *NodeHistoryRequest) (*NodeHistoryResponse, error)
 	mustEmbedUnimplementedMasterTrackerServiceServer()
 }
 
@@ -114,6 +266,9 @@ func (UnimplementedMasterTrackerServiceServer) Heartbeat(context.Context, *Heart
 func (UnimplementedMasterTrackerServiceServer) UploadFile(context.Context, *UploadFileRequest) (*UploadFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedMasterTrackerServiceServer) PlaceBlock(context.Context, *PlaceBlockRequest) (*BlockPlacement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceBlock not implemented")
+}
 func (UnimplementedMasterTrackerServiceServer) RegisterFile(context.Context, *RegisterFileRequest) (*RegisterFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterFile not implemented")
 }
@@ -123,6 +278,39 @@ func (UnimplementedMasterTrackerServiceServer) DownloadFile(context.Context, *Do
 func (UnimplementedMasterTrackerServiceServer) Join(context.Context, *JoinRequest) (*SuccessResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Join not implemented")
 }
+func (UnimplementedMasterTrackerServiceServer) ReportCorruptBlock(context.Context, *CorruptBlockRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportCorruptBlock not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) DeleteFile(context.Context, *DeleteFileRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) RenameFile(context.Context, *RenameFileRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFile not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) StatFile(context.Context, *StatFileRequest) (*StatFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatFile not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) Mkdir(context.Context, *MkdirRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mkdir not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) Rmdir(context.Context, *RmdirRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rmdir not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFiles not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) SetReplication(context.Context, *SetReplicationRequest) (*SuccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetReplication not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) DecommissionNode(context.Context, *DecommissionNodeRequest) (*DecommissionNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecommissionNode not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedMasterTrackerServiceServer) NodeHistory(context.Context, *NodeHistoryRequest) (*NodeHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NodeHistory not implemented")
+}
 func (UnimplementedMasterTrackerServiceServer) mustEmbedUnimplementedMasterTrackerServiceServer() {}
 
 // UnsafeMasterTrackerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -172,6 +360,24 @@ func _MasterTrackerService_UploadFile_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MasterTrackerService_PlaceBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).PlaceBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/PlaceBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).PlaceBlock(ctx, req.(*PlaceBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MasterTrackerService_RegisterFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterFileRequest)
 	if err := dec(in); err != nil {
@@ -226,6 +432,204 @@ func _MasterTrackerService_Join_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MasterTrackerService_ReportCorruptBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CorruptBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).ReportCorruptBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/ReportCorruptBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).ReportCorruptBlock(ctx, req.(*CorruptBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/DeleteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_RenameFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).RenameFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/RenameFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).RenameFile(ctx, req.(*RenameFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_StatFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).StatFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/StatFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).StatFile(ctx, req.(*StatFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_Mkdir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MkdirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).Mkdir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/Mkdir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).Mkdir(ctx, req.(*MkdirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_Rmdir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RmdirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).Rmdir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/Rmdir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).Rmdir(ctx, req.(*RmdirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).ListFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/ListFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).ListFiles(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_SetReplication_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReplicationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).SetReplication(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/SetReplication",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).SetReplication(ctx, req.(*SetReplicationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_DecommissionNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).DecommissionNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/DecommissionNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).DecommissionNode(ctx, req.(*DecommissionNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/Rebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).Rebalance(ctx, req.(*RebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MasterTrackerService_NodeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MasterTrackerServiceServer).NodeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master.MasterTrackerService/NodeHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MasterTrackerServiceServer).NodeHistory(ctx, req.(*NodeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MasterTrackerService_ServiceDesc is the grpc.ServiceDesc for MasterTrackerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -241,6 +645,10 @@ var MasterTrackerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadFile",
 			Handler:    _MasterTrackerService_UploadFile_Handler,
 		},
+		{
+			MethodName: "PlaceBlock",
+			Handler:    _MasterTrackerService_PlaceBlock_Handler,
+		},
 		{
 			MethodName: "RegisterFile",
 			Handler:    _MasterTrackerService_RegisterFile_Handler,
@@ -253,6 +661,50 @@ var MasterTrackerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Join",
 			Handler:    _MasterTrackerService_Join_Handler,
 		},
+		{
+			MethodName: "ReportCorruptBlock",
+			Handler:    _MasterTrackerService_ReportCorruptBlock_Handler,
+		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _MasterTrackerService_DeleteFile_Handler,
+		},
+		{
+			MethodName: "RenameFile",
+			Handler:    _MasterTrackerService_RenameFile_Handler,
+		},
+		{
+			MethodName: "StatFile",
+			Handler:    _MasterTrackerService_StatFile_Handler,
+		},
+		{
+			MethodName: "Mkdir",
+			Handler:    _MasterTrackerService_Mkdir_Handler,
+		},
+		{
+			MethodName: "Rmdir",
+			Handler:    _MasterTrackerService_Rmdir_Handler,
+		},
+		{
+			MethodName: "ListFiles",
+			Handler:    _MasterTrackerService_ListFiles_Handler,
+		},
+		{
+			MethodName: "SetReplication",
+			Handler:    _MasterTrackerService_SetReplication_Handler,
+		},
+		{
+			MethodName: "DecommissionNode",
+			Handler:    _MasterTrackerService_DecommissionNode_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _MasterTrackerService_Rebalance_Handler,
+		},
+		{
+			MethodName: "NodeHistory",
+			Handler:    _MasterTrackerService_NodeHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "src/grpc/master/master.proto",
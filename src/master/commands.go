@@ -0,0 +1,125 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+const (
+	// maxCommandsPerHeartbeat bounds the commands sent with one heartbeat response
+	maxCommandsPerHeartbeat = 100
+	// copyTimeout is how long a scheduled copy of a block is waited for before it is scheduled again
+	copyTimeout = time.Minute
+)
+
+// commandQueue holds the commands waiting for the next heartbeat of each data keeper
+type commandQueue struct {
+	mu       sync.Mutex
+	commands map[int32][]*pb.Command
+	copies   map[string]map[int32]time.Time // destinations of the copies scheduled for each block, and when
+}
+
+var commands = &commandQueue{commands: make(map[int32][]*pb.Command), copies: make(map[string]map[int32]time.Time)}
+
+// push queues a command for a data keeper
+func (q *commandQueue) push(dataNodeId int32, command *pb.Command) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.commands[dataNodeId] = append(q.commands[dataNodeId], command)
+}
+
+// copyTo queues a copy of blockId from sourceId to destinationId and remembers it until the copy registers
+func (q *commandQueue) copyTo(blockId string, sourceId int32, destinationId int32) {
+	destination, _ := store.Node(destinationId)
+	command := replicateCommand(blockId, destination.downloadAddress)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.copies[blockId] == nil {
+		q.copies[blockId] = make(map[int32]time.Time)
+	}
+	q.copies[blockId][destinationId] = time.Now()
+	q.commands[sourceId] = append(q.commands[sourceId], command)
+}
+
+// take removes and returns the next commands of a data keeper
+func (q *commandQueue) take(dataNodeId int32) []*pb.Command {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queued := q.commands[dataNodeId]
+	if len(queued) <= maxCommandsPerHeartbeat {
+		delete(q.commands, dataNodeId)
+		return queued
+	}
+	q.commands[dataNodeId] = queued[maxCommandsPerHeartbeat:]
+	return queued[:maxCommandsPerHeartbeat]
+}
+
+// copying returns the destinations of the copies of blockId scheduled less than copyTimeout ago
+func (q *commandQueue) copying(blockId string) []int32 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	destinations := make([]int32, 0)
+	for destinationId, scheduled := range q.copies[blockId] {
+		if time.Since(scheduled) > copyTimeout {
+			delete(q.copies[blockId], destinationId)
+			continue
+		}
+		destinations = append(destinations, destinationId)
+	}
+	if len(q.copies[blockId]) == 0 {
+		delete(q.copies, blockId)
+	}
+	return destinations
+}
+
+// copied forgets the scheduled copy of blockId to dataNodeId once the replica is registered
+func (q *commandQueue) copied(blockId string, dataNodeId int32) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.copies[blockId], dataNodeId)
+	if len(q.copies[blockId]) == 0 {
+		delete(q.copies, blockId)
+	}
+}
+
+// cancelCopies forgets the scheduled copies of the given blocks and drops the copy commands not sent yet
+func (q *commandQueue) cancelCopies(blockIds []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cancelled := make(map[string]bool, len(blockIds))
+	for _, blockId := range blockIds {
+		cancelled[blockId] = true
+		delete(q.copies, blockId)
+	}
+	for dataNodeId, queued := range q.commands {
+		kept := make([]*pb.Command, 0, len(queued))
+		for _, command := range queued {
+			if command.GetType() != pb.Command_REPLICATE || !cancelled[command.GetBlockId()] {
+				kept = append(kept, command)
+			}
+		}
+		q.commands[dataNodeId] = kept
+	}
+}
+
+// reset drops every queued command, as a master that stopped leading no longer receives heartbeats
+func (q *commandQueue) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.commands = make(map[int32][]*pb.Command)
+	q.copies = make(map[string]map[int32]time.Time)
+}
+
+func replicateCommand(blockId string, grpcAddress string) *pb.Command {
+	return &pb.Command{Type: pb.Command_REPLICATE, BlockId: blockId, GrpcAddress: grpcAddress, Checksum: blockChecksum(blockId)}
+}
+
+func deleteCommand(blockId string) *pb.Command {
+	return &pb.Command{Type: pb.Command_DELETE, BlockId: blockId}
+}
+
+func scrubCommand(blockId string) *pb.Command {
+	return &pb.Command{Type: pb.Command_SCRUB, BlockId: blockId}
+}
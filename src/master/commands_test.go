@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+func TestCancelCopies(t *testing.T) {
+	useNodes(t, randomPolicy{}, 100, 100, 100)
+	q := &commandQueue{commands: make(map[int32][]*pb.Command), copies: make(map[string]map[int32]time.Time)}
+	q.copyTo("b1", 1, 2)
+	q.copyTo("b2", 1, 3)
+	q.push(1, deleteCommand("b1"))
+
+	q.cancelCopies([]string{"b1"})
+	if len(q.copying("b1")) != 0 {
+		t.Error("cancelled copy still scheduled")
+	}
+	if destinations := q.copying("b2"); len(destinations) != 1 || destinations[0] != 3 {
+		t.Errorf("copies of b2 go to %v, want [3]", destinations)
+	}
+	queued := q.take(1)
+	if len(queued) != 2 || queued[0].GetBlockId() != "b2" || queued[1].GetType() != pb.Command_DELETE {
+		t.Errorf("queued commands %v, want the copy of b2 and the deletion of b1", queued)
+	}
+}
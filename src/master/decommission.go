@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	pb "src/grpc/master"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DecommissionNode starts retiring a data keeper: no new blocks are placed on it and every block it holds
+// is copied to other nodes. Calling it again reports the progress, and cancel puts the node back in service.
+func (s *masterServer) DecommissionNode(ctx context.Context, req *pb.DecommissionNodeRequest) (*pb.DecommissionNodeResponse, error) {
+	id := req.GetDataNodeId()
+	node, ok := store.Node(id)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "data keeper %d not found", id)
+	}
+	if node.decommissioning == req.GetCancel() {
+		if err := raft.propose(logEntry{Op: opDecommission, Node: &nodeRecord{Id: id, Decommissioning: !req.GetCancel()}}); err != nil {
+			fmt.Println("Error logging decommission:", err)
+			return nil, err
+		}
+		if req.GetCancel() {
+			fmt.Printf("[DECOMMISSION] Data Keeper %d is back in service\n", id)
+		} else {
+			fmt.Printf("[DECOMMISSION] Decommissioning Data Keeper %d\n", id)
+			go drainNode(id)
+		}
+	}
+	return decommissionProgress(id), nil
+}
+
+// activeHolders returns the holders that are not being decommissioned
+func activeHolders(holders []int32) []int32 {
+	active := make([]int32, 0, len(holders))
+	for _, id := range holders {
+		if node, _ := store.Node(id); !node.decommissioning {
+			active = append(active, id)
+		}
+	}
+	return active
+}
+
+// aliveHolders returns the alive data nodes holding a replica of blockId
+func aliveHolders(blockId string) []int32 {
+	holders := make([]int32, 0)
+	for _, replica := range store.Replicas(blockId) {
+		if node, _ := store.Node(replica.DataNodeId); node.isAlive {
+			holders = append(holders, replica.DataNodeId)
+		}
+	}
+	return holders
+}
+
+// blockTargets maps every block of a stored file to the replication target of the file
+func blockTargets() map[string]int {
+	targets := make(map[string]int)
+	for _, file := range store.FileList() {
+		target := replicationTarget(file)
+		for _, blockId := range file.Blocks {
+			targets[blockId] = target
+		}
+	}
+	return targets
+}
+
+// drainNode copies the blocks of a node being decommissioned right away instead of waiting for
+// the replication loop, which takes care of the copies that fail here
+func drainNode(id int32) {
+	targets := blockTargets()
+	for _, replica := range store.ReplicasOnNode(id) {
+		if target, ok := targets[replica.BlockId]; ok {
+			chooseNodesToReplicate(replica.BlockId, target, aliveHolders(replica.BlockId)...)
+		}
+	}
+	progress := decommissionProgress(id)
+	fmt.Printf("[DECOMMISSION] Data Keeper %d: %d of %d blocks still need copies\n", id, progress.GetRemainingBlocks(), progress.GetTotalBlocks())
+}
+
+// decommissionProgress counts the blocks on a node that would fall short of their replication
+// target without it. Blocks of running uploads count until their file is stored.
+func decommissionProgress(id int32) *pb.DecommissionNodeResponse {
+	node, _ := store.Node(id)
+	targets := blockTargets()
+	uploading := uploadSessions.blockIds()
+	var total, remaining int32
+	for _, replica := range store.ReplicasOnNode(id) {
+		target, ok := targets[replica.BlockId]
+		if !ok && !uploading[replica.BlockId] {
+			continue
+		}
+		total++
+		if !ok || len(activeHolders(aliveHolders(replica.BlockId))) < target {
+			remaining++
+		}
+	}
+	return &pb.DecommissionNodeResponse{
+		DataNodeId:      id,
+		Decommissioning: node.decommissioning,
+		TotalBlocks:     total,
+		RemainingBlocks: remaining,
+		SafeToRemove:    node.decommissioning && remaining == 0,
+	}
+}
+
+// drained remembers the nodes already announced as safe to remove
+var drained = struct {
+	sync.Mutex
+	nodes map[int32]bool
+}{nodes: make(map[int32]bool)}
+
+// reportDecommissions logs the progress of every node being decommissioned
+func reportDecommissions() {
+	drained.Lock()
+	defer drained.Unlock()
+	for _, id := range store.NodeIds() {
+		node, _ := store.Node(id)
+		if !node.decommissioning {
+			delete(drained.nodes, id)
+			continue
+		}
+		progress := decommissionProgress(id)
+		if !progress.GetSafeToRemove() {
+			delete(drained.nodes, id)
+			fmt.Printf("[DECOMMISSION] Data Keeper %d: %d of %d blocks still need copies\n", id, progress.GetRemainingBlocks(), progress.GetTotalBlocks())
+		} else if !drained.nodes[id] {
+			drained.nodes[id] = true
+			fmt.Printf("[DECOMMISSION] Data Keeper %d can be removed safely\n", id)
+		}
+	}
+}
+
+func applyDecommission(id int32, decommissioning bool) {
+	store.SetDecommissioning(id, decommissioning)
+}
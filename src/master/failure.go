@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+// nodeState is the liveness of a data node as seen by the failure detector. Suspect nodes still
+// count as holders of their replicas but receive no new blocks.
+type nodeState string
+
+const (
+	stateAlive   nodeState = "alive"
+	stateSuspect nodeState = "suspect"
+	stateDead    nodeState = "dead"
+)
+
+const (
+	// maxNodeHistory bounds the state changes kept for NodeHistory
+	maxNodeHistory = 1000
+	// phiWindow is the number of heartbeat intervals the phi accrual detector learns from
+	phiWindow = 100
+	// phiMinSamples is the number of intervals needed before phi is trusted over the suspect timeout
+	phiMinSamples = 5
+	// phiMinStdDev keeps phi from exploding when heartbeats arrive very regularly
+	phiMinStdDev = 100 * time.Millisecond
+)
+
+// keeperHeartbeatInterval reads HEARTBEAT_INTERVAL (in seconds), how often data keepers send heartbeats
+func keeperHeartbeatInterval() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("HEARTBEAT_INTERVAL"), 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// suspectTimeout reads SUSPECT_TIMEOUT (in seconds), how long a node may stay silent before it is suspected
+func suspectTimeout() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("SUSPECT_TIMEOUT"), 64)
+	if err != nil || seconds <= 0 {
+		return 3 * keeperHeartbeatInterval()
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// deadTimeout reads DEAD_TIMEOUT (in seconds), how long a node may stay silent before it is declared dead
+// and its replicas are copied elsewhere
+func deadTimeout() time.Duration {
+	seconds, err := strconv.ParseFloat(os.Getenv("DEAD_TIMEOUT"), 64)
+	if err != nil || seconds <= 0 {
+		return 10 * keeperHeartbeatInterval()
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// phiThreshold reads PHI_THRESHOLD, the suspicion level above which the phi accrual detector suspects a node
+func phiThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("PHI_THRESHOLD"), 64)
+	if err != nil || threshold <= 0 {
+		return 8
+	}
+	return threshold
+}
+
+// usePhi reports whether FAILURE_DETECTOR selects the phi accrual detector instead of the suspect timeout
+func usePhi() bool {
+	return os.Getenv("FAILURE_DETECTOR") == "phi"
+}
+
+// arrivals is the heartbeat history of one data node
+type arrivals struct {
+	last      time.Time
+	intervals []time.Duration
+	heard     bool // false while the silence is counted from when this master took the lead
+}
+
+// nodeStateChange is one entry of the node history
+type nodeStateChange struct {
+	id     int32
+	from   nodeState
+	to     nodeState
+	at     time.Time
+	reason string
+}
+
+// failureDetector tracks when heartbeats arrive and remembers every change of node state
+type failureDetector struct {
+	mu       sync.Mutex
+	arrivals map[int32]*arrivals
+	history  []nodeStateChange
+}
+
+var detector = &failureDetector{arrivals: make(map[int32]*arrivals)}
+
+// heartbeat records a heartbeat from a data node
+func (d *failureDetector) heartbeat(id int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	a, ok := d.arrivals[id]
+	if !ok || !a.heard {
+		d.arrivals[id] = &arrivals{last: now, heard: true}
+		return
+	}
+	a.intervals = append(a.intervals, now.Sub(a.last))
+	if len(a.intervals) > phiWindow {
+		a.intervals = a.intervals[1:]
+	}
+	a.last = now
+}
+
+// joined starts the heartbeat history of a node over, so the time it was down is not taken as an interval
+func (d *failureDetector) joined(id int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.arrivals[id] = &arrivals{last: time.Now(), heard: true}
+}
+
+// lead starts counting the silence of every node from now, when this master becomes the leader.
+// Nodes that died while another master led never send a heartbeat here and are declared dead in time.
+func (d *failureDetector) lead(ids []int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for _, id := range ids {
+		if _, ok := d.arrivals[id]; !ok {
+			d.arrivals[id] = &arrivals{last: now}
+		}
+	}
+}
+
+// heard reports whether a heartbeat of a node reached this master since it took the lead
+func (d *failureDetector) heard(id int32) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	a, ok := d.arrivals[id]
+	return ok && a.heard
+}
+
+// reset forgets every heartbeat, as they went to another master while this one was not leading
+func (d *failureDetector) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.arrivals = make(map[int32]*arrivals)
+}
+
+// silence returns how long ago the last heartbeat of a node arrived, and the phi suspicion level
+// if enough heartbeats were seen to compute it
+func (d *failureDetector) silence(id int32) (time.Duration, float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	a, ok := d.arrivals[id]
+	if !ok {
+		return 0, 0, false
+	}
+	elapsed := time.Since(a.last)
+	if len(a.intervals) < phiMinSamples {
+		return elapsed, math.NaN(), true
+	}
+	return elapsed, phi(elapsed, a.intervals), true
+}
+
+// phi is the suspicion level of the phi accrual failure detector: -log10 of the probability that a
+// heartbeat arrives later than elapsed, with the intervals taken as normally distributed
+func phi(elapsed time.Duration, intervals []time.Duration) float64 {
+	var sum float64
+	for _, interval := range intervals {
+		sum += float64(interval)
+	}
+	mean := sum / float64(len(intervals))
+	var variance float64
+	for _, interval := range intervals {
+		variance += (float64(interval) - mean) * (float64(interval) - mean)
+	}
+	stdDev := math.Max(math.Sqrt(variance/float64(len(intervals))), float64(phiMinStdDev))
+
+	// Logistic approximation of the normal distribution
+	y := (float64(elapsed) - mean) / stdDev
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if float64(elapsed) > mean {
+		return -math.Log10(e / (1 + e))
+	}
+	return -math.Log10(1 - 1/(1+e))
+}
+
+func (d *failureDetector) record(change nodeStateChange) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.history = append(d.history, change)
+	if len(d.history) > maxNodeHistory {
+		d.history = d.history[len(d.history)-maxNodeHistory:]
+	}
+}
+
+// changes returns the latest limit state changes of a node, or of every node when id is 0, oldest first
+func (d *failureDetector) changes(id int32, limit int) []nodeStateChange {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	changes := make([]nodeStateChange, 0)
+	for i := len(d.history) - 1; i >= 0 && (limit <= 0 || len(changes) < limit); i-- {
+		if id == 0 || d.history[i].id == id {
+			changes = append(changes, d.history[i])
+		}
+	}
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	return changes
+}
+
+// setNodeState changes the state of a data node and records the change in the history
+func setNodeState(id int32, state nodeState, reason string) {
+	node, ok := store.Node(id)
+	if !ok {
+		return
+	}
+	store.SetState(id, state)
+	detector.record(nodeStateChange{id: id, from: node.state, to: state, at: time.Now(), reason: reason})
+	fmt.Printf("Node %d is %s (%s)\n", id, state, reason)
+}
+
+// judgeSilence returns the state of a node whose last heartbeat arrived elapsed ago, given its phi
+// suspicion level or NaN, and the reason for it
+func judgeSilence(elapsed time.Duration, suspicion float64) (nodeState, string) {
+	if elapsed > deadTimeout() {
+		return stateDead, fmt.Sprintf("no heartbeat for %v", elapsed.Round(time.Millisecond))
+	}
+	if usePhi() && !math.IsNaN(suspicion) {
+		if suspicion > phiThreshold() {
+			return stateSuspect, fmt.Sprintf("phi %.1f", suspicion)
+		}
+		return stateAlive, "heartbeats resumed"
+	}
+	if elapsed > suspectTimeout() {
+		return stateSuspect, fmt.Sprintf("no heartbeat for %v", elapsed.Round(time.Millisecond))
+	}
+	return stateAlive, "heartbeats resumed"
+}
+
+// checkAliveDataNodes moves data nodes between alive, suspect and dead as their heartbeats arrive or stop.
+// A node is suspected after SUSPECT_TIMEOUT, or when phi exceeds PHI_THRESHOLD with FAILURE_DETECTOR=phi,
+// and is only declared dead after DEAD_TIMEOUT.
+func checkAliveDataNodes() {
+	leading := false
+	for {
+		time.Sleep(keeperHeartbeatInterval() / 2)
+		// Data keepers only send heartbeats to the leader
+		if !raft.isLeader() {
+			leading = false
+			detector.reset()
+			commands.reset()
+			continue
+		}
+		if !leading {
+			detector.lead(store.NodeIds())
+			leading = true
+		}
+		for _, id := range store.NodeIds() {
+			elapsed, suspicion, seen := detector.silence(id)
+			if !seen {
+				continue
+			}
+			state, reason := judgeSilence(elapsed, suspicion)
+			// A node is only declared alive once this master hears from it
+			if state == stateAlive && !detector.heard(id) {
+				continue
+			}
+			if node, _ := store.Node(id); node.state != state {
+				setNodeState(id, state, reason)
+			}
+		}
+	}
+}
+
+// NodeHistory reports the current state of every data node and the latest state changes
+func (s *masterServer) NodeHistory(ctx context.Context, req *pb.NodeHistoryRequest) (*pb.NodeHistoryResponse, error) {
+	resp := &pb.NodeHistoryResponse{}
+	for _, id := range store.NodeIds() {
+		if req.GetDataNodeId() != 0 && id != req.GetDataNodeId() {
+			continue
+		}
+		node, _ := store.Node(id)
+		status := &pb.NodeStatus{
+			DataNodeId:      id,
+			State:           string(node.state),
+			Decommissioning: node.decommissioning,
+			Capacity:        node.capacity,
+			Used:            node.used,
+			InFlight:        node.inFlight,
+		}
+		if elapsed, suspicion, seen := detector.silence(id); seen && detector.heard(id) {
+			status.LastHeartbeat = elapsed.Milliseconds()
+			if !math.IsNaN(suspicion) {
+				status.Phi = suspicion
+			}
+		}
+		resp.Nodes = append(resp.Nodes, status)
+	}
+	for _, change := range detector.changes(req.GetDataNodeId(), int(req.GetLimit())) {
+		resp.Changes = append(resp.Changes, &pb.NodeStateChange{
+			DataNodeId: change.id,
+			From:       string(change.from),
+			To:         string(change.to),
+			Time:       change.at.Unix(),
+			Reason:     change.reason,
+		})
+	}
+	return resp, nil
+}
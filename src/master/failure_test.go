@@ -0,0 +1,121 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPhi(t *testing.T) {
+	regular := make([]time.Duration, 10)
+	for i := range regular {
+		regular[i] = time.Second
+	}
+	for _, c := range []struct {
+		name     string
+		elapsed  time.Duration
+		min, max float64
+	}{
+		{"right after a heartbeat", 0, 0, 0.01},
+		{"at the mean interval", time.Second, 0.29, 0.32},
+		{"one deviation late", 1100 * time.Millisecond, 0.7, 1.0},
+		{"well overdue", 2 * time.Second, 8, math.Inf(1)},
+	} {
+		if got := phi(c.elapsed, regular); got < c.min || got > c.max {
+			t.Errorf("%s: phi %.2f, want between %.2f and %.2f", c.name, got, c.min, c.max)
+		}
+	}
+	// Irregular heartbeats make the same silence less suspicious
+	irregular := []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond, 500 * time.Millisecond, 1500 * time.Millisecond}
+	if phi(2*time.Second, irregular) >= phi(2*time.Second, regular) {
+		t.Error("irregular heartbeats as suspicious as regular ones")
+	}
+}
+
+func TestJudgeSilence(t *testing.T) {
+	t.Setenv("HEARTBEAT_INTERVAL", "1")
+	for _, c := range []struct {
+		name      string
+		phi       string
+		elapsed   time.Duration
+		suspicion float64
+		want      nodeState
+	}{
+		{"recent heartbeat", "", 500 * time.Millisecond, math.NaN(), stateAlive},
+		{"past the suspect timeout", "", 4 * time.Second, math.NaN(), stateSuspect},
+		{"past the dead timeout", "", 11 * time.Second, math.NaN(), stateDead},
+		{"phi below the threshold", "phi", 4 * time.Second, 2, stateAlive},
+		{"phi above the threshold", "phi", 2 * time.Second, 9, stateSuspect},
+		{"phi without enough samples", "phi", 4 * time.Second, math.NaN(), stateSuspect},
+		{"phi past the dead timeout", "phi", 11 * time.Second, 2, stateDead},
+	} {
+		t.Setenv("FAILURE_DETECTOR", c.phi)
+		if got, reason := judgeSilence(c.elapsed, c.suspicion); got != c.want {
+			t.Errorf("%s: %s (%s), want %s", c.name, got, reason, c.want)
+		}
+	}
+}
+
+func TestFailureDetector(t *testing.T) {
+	d := &failureDetector{arrivals: make(map[int32]*arrivals)}
+	d.lead([]int32{1, 2})
+	if d.heard(1) {
+		t.Error("node heard before its first heartbeat")
+	}
+	if _, _, seen := d.silence(1); !seen {
+		t.Error("silence of a known node not counted from the start of the lead")
+	}
+
+	for i := 0; i < phiMinSamples; i++ {
+		d.heartbeat(1)
+	}
+	if !d.heard(1) || d.heard(2) {
+		t.Error("heartbeat credited to the wrong node")
+	}
+	// The first heartbeat after taking the lead starts the history, it is not an interval
+	if _, suspicion, _ := d.silence(1); !math.IsNaN(suspicion) {
+		t.Errorf("phi %.2f from %d intervals, want none before %d", suspicion, phiMinSamples-1, phiMinSamples)
+	}
+	d.heartbeat(1)
+	if _, suspicion, _ := d.silence(1); math.IsNaN(suspicion) {
+		t.Error("no phi after enough heartbeats")
+	}
+
+	d.joined(1)
+	if _, suspicion, _ := d.silence(1); !math.IsNaN(suspicion) {
+		t.Error("intervals kept across a rejoin")
+	}
+	d.reset()
+	if _, _, seen := d.silence(1); seen {
+		t.Error("heartbeats kept after the lead was lost")
+	}
+}
+
+func TestNodeStateChanges(t *testing.T) {
+	d := &failureDetector{arrivals: make(map[int32]*arrivals)}
+	for i, to := range []nodeState{stateSuspect, stateDead, stateAlive} {
+		d.record(nodeStateChange{id: 1, to: to, reason: string(to)})
+		d.record(nodeStateChange{id: 2, to: to, at: time.Unix(int64(i), 0)})
+	}
+	for _, c := range []struct {
+		id    int32
+		limit int
+		want  []nodeState
+	}{
+		{1, 0, []nodeState{stateSuspect, stateDead, stateAlive}},
+		{1, 2, []nodeState{stateDead, stateAlive}},
+		{0, 1, []nodeState{stateAlive}},
+		{3, 0, nil},
+	} {
+		changes := d.changes(c.id, c.limit)
+		if len(changes) != len(c.want) {
+			t.Errorf("node %d limit %d: %d changes, want %d", c.id, c.limit, len(changes), len(c.want))
+			continue
+		}
+		for i, change := range changes {
+			if change.to != c.want[i] || (c.id != 0 && change.id != c.id) {
+				t.Errorf("node %d limit %d: change %d is %+v, want a change to %s", c.id, c.limit, i, change, c.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+// deletionQueue holds blocks found on data keepers that the master does not track.
+// They are deleted after a grace period, unless they were registered in the meantime.
+type deletionQueue struct {
+	mu     sync.Mutex
+	blocks map[int32]map[string]time.Time
+}
+
+var pendingDeletions = &deletionQueue{blocks: make(map[int32]map[string]time.Time)}
+
+// schedule queues blockId for deletion from a data keeper, keeping the time it was first queued
+func (q *deletionQueue) schedule(dataNodeId int32, blockId string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.blocks[dataNodeId] == nil {
+		q.blocks[dataNodeId] = make(map[string]time.Time)
+	}
+	if _, ok := q.blocks[dataNodeId][blockId]; !ok {
+		q.blocks[dataNodeId][blockId] = time.Now()
+	}
+}
+
+// due removes and returns the blocks queued for longer than grace
+func (q *deletionQueue) due(grace time.Duration) map[int32][]string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	due := make(map[int32][]string)
+	for dataNodeId, blocks := range q.blocks {
+		for blockId, queued := range blocks {
+			if time.Since(queued) >= grace {
+				due[dataNodeId] = append(due[dataNodeId], blockId)
+				delete(blocks, blockId)
+			}
+		}
+	}
+	return due
+}
+
+// untrackedBlockGrace reads UNTRACKED_BLOCK_GRACE (in seconds), how long an untracked block is kept
+// before it is deleted
+func untrackedBlockGrace() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("UNTRACKED_BLOCK_GRACE"))
+	if err != nil || seconds < 0 {
+		return time.Minute
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// trackedBlocks maps every block of a stored file or of an upload in progress to its expected checksum.
+// The checksum is empty for blocks that were not stored yet.
+func trackedBlocks() map[string]string {
+	tracked := make(map[string]string)
+	for blockId := range uploadSessions.blockIds() {
+		tracked[blockId] = ""
+	}
+	for _, file := range store.FileList() {
+		for i, blockId := range file.Blocks {
+			if i < len(file.BlockChecksums) {
+				tracked[blockId] = file.BlockChecksums[i]
+			} else {
+				tracked[blockId] = ""
+			}
+		}
+	}
+	return tracked
+}
+
+// holds reports whether a replica of blockId on dataNodeId is registered
+func holds(dataNodeId int32, blockId string) bool {
+	for _, replica := range store.Replicas(blockId) {
+		if replica.DataNodeId == dataNodeId {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileInventory compares the blocks a data keeper found on disk when it started with the tables.
+// Replicas the keeper lost are forgotten, blocks of known files are adopted as replicas, and blocks
+// the master does not track are scheduled for deletion.
+func reconcileInventory(dataNodeId int32, blocks []*pb.StoredBlock) {
+	// A leader that has not replayed the log yet would take every block for an orphan
+	if !raft.caughtUp() {
+		fmt.Printf("[INVENTORY] Not reconciling Data Keeper %d before the log is replayed\n", dataNodeId)
+		return
+	}
+	tracked := trackedBlocks()
+	uploading := uploadSessions.blockIds()
+	reported := make(map[string]bool, len(blocks))
+	adopted, scheduled := 0, 0
+	for _, block := range blocks {
+		blockId := block.GetBlockId()
+		reported[blockId] = true
+		if holds(dataNodeId, blockId) {
+			continue
+		}
+		expected, ok := tracked[blockId]
+		if !ok {
+			fmt.Printf("[INVENTORY] Block %s on Data Keeper %d belongs to no file\n", blockId, dataNodeId)
+			pendingDeletions.schedule(dataNodeId, blockId)
+			scheduled++
+			continue
+		}
+		// Blocks of running uploads are registered by the upload itself
+		if uploading[blockId] {
+			continue
+		}
+		if expected == "" {
+			expected = blockChecksum(blockId)
+		}
+		if expected != "" && expected != block.GetChecksum() {
+			fmt.Printf("[INVENTORY] Block %s on Data Keeper %d does not match its checksum\n", blockId, dataNodeId)
+			pendingDeletions.schedule(dataNodeId, blockId)
+			scheduled++
+			continue
+		}
+		replica := BlockReplica{BlockId: blockId, DataNodeId: dataNodeId, FilePath: block.GetFilePath(), Size: block.GetSize(), Checksum: block.GetChecksum()}
+		if err := raft.propose(logEntry{Op: opRegisterBlock, Replica: &replica}); err != nil {
+			fmt.Println("Error logging block registration:", err)
+			continue
+		}
+		adopted++
+	}
+
+	forgotten := 0
+	for _, replica := range store.ReplicasOnNode(dataNodeId) {
+		if reported[replica.BlockId] {
+			continue
+		}
+		err := raft.propose(logEntry{Op: opRemoveBlock, Replica: &BlockReplica{BlockId: replica.BlockId, DataNodeId: dataNodeId}})
+		if err != nil {
+			fmt.Println("Error logging block removal:", err)
+			continue
+		}
+		forgotten++
+	}
+	fmt.Printf("[INVENTORY] Data Keeper %d reported %d blocks: %d adopted, %d lost, %d scheduled for deletion\n",
+		dataNodeId, len(blocks), adopted, forgotten, scheduled)
+}
+
+// scheduleUntracked queues the blocks of a block report that belong to no file
+func scheduleUntracked(dataNodeId int32, blocks []string) {
+	if !raft.caughtUp() {
+		return
+	}
+	tracked := trackedBlocks()
+	for _, blockId := range blocks {
+		if _, ok := tracked[blockId]; !ok && !holds(dataNodeId, blockId) {
+			pendingDeletions.schedule(dataNodeId, blockId)
+		}
+	}
+}
+
+// deleteUntracked has the data keepers delete the queued blocks once their grace period is over
+func deleteUntracked() {
+	for {
+		time.Sleep(10 * time.Second)
+		if !raft.caughtUp() {
+			continue
+		}
+		for dataNodeId, blockIds := range pendingDeletions.due(untrackedBlockGrace()) {
+			node, ok := store.Node(dataNodeId)
+			if !ok || !node.isAlive {
+				continue
+			}
+			uploading := uploadSessions.blockIds()
+			for _, blockId := range blockIds {
+				// The block may have been registered while it waited
+				if uploading[blockId] || holds(dataNodeId, blockId) {
+					continue
+				}
+				commands.push(dataNodeId, deleteCommand(blockId))
+				fmt.Printf("[INVENTORY] Deleting untracked block %s from Data Keeper %d\n", blockId, dataNodeId)
+			}
+		}
+	}
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"net"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	pb "src/grpc/master" // Import the generated package
@@ -15,61 +20,201 @@ import (
 
 	dk "src/grpc/datakeeper"
 
+	rf "src/grpc/raft"
+
+	"src/connpool"
+	"src/masters"
+
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-var clientPort string
-
 type masterServer struct {
 	pb.UnimplementedMasterTrackerServiceServer
 }
 
 type dataNode struct {
-	address    string
 	downloadAddress string
-	isAlive    bool
+	isAlive    bool // alive or suspect
+	state      nodeState
+	decommissioning bool // no new blocks are placed on the node while it is drained
+	rack       string
+	zone       string
+	nodeStats  // load from the latest heartbeat
 }
 type FileMetadata struct {
-	FileName   string // File name
-	DataNodeId int32  // Data Keeper node where the file is stored
-	FilePath   string // File path on the Data Keeper node
-	Size 	   int64  // File size
+	FileName       string    // Absolute path of the file, including its extension
+	ContentType    string    // MIME type of the file
+	Replication    int32     // Copies to keep of every block, 0 to follow the directory
+	Size           int64     // File size
+	BlockSize      int64     // Size of every block but the last
+	Blocks         []string  // Block ids in file order
+	Checksum       string    // Hex SHA-256 of the whole file, computed by the client
+	BlockChecksums []string  // Hex SHA-256 of every block, in file order
+	CreatedAt      time.Time // When the upload completed
+	ModifiedAt     time.Time // Last upload or rename
+}
+type BlockReplica struct {
+	BlockId    string // Block id
+	DataNodeId int32  // Data Keeper node where the block is stored
+	FilePath   string // Block path on the Data Keeper node
+	Size       int64  // Block size
+	Checksum   string // Hex SHA-256 of the block
 }
 
-var dataNodesHeartbeats = make(map[int32]int32)
-var fileLookupTable = make([]FileMetadata, 0)
-var dataNodeLookupTable = make(map[int32]dataNode)
+// store holds the namespace, file and data node tables shared by the handlers and the background loops
+var store MetadataStore = newMemoryStore()
 
-var allIds = make([]int32, 0)
+var raft *raftNode
+
+// blockSize reads BLOCK_SIZE (in bytes) from the environment
+func blockSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv("BLOCK_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		return 64 << 20
+	}
+	return size
+}
+
+// newBlockId returns an opaque block id. Data keepers store blocks under these ids,
+// so their disk layout does not depend on file paths.
+func newBlockId() string {
+	return "blk_" + newSessionId()
+}
+
+// splitIntoBlocks returns the block list of a file of the given size. An empty file still has one empty block.
+func splitIntoBlocks(fileName string, size int64, blockSize int64) FileMetadata {
+	file := FileMetadata{FileName: fileName, Size: size, BlockSize: blockSize}
+	count := int((size + blockSize - 1) / blockSize)
+	if count == 0 {
+		count = 1
+	}
+	for i := 0; i < count; i++ {
+		file.Blocks = append(file.Blocks, newBlockId())
+	}
+	return file
+}
+
+// blockRange returns the offset and size of the index-th block of file
+func blockRange(file FileMetadata, index int) (int64, int64) {
+	offset := int64(index) * file.BlockSize
+	return offset, min(file.BlockSize, file.Size-offset)
+}
 
 func (s *masterServer) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
 	id := req.GetDataNodeId()
-	dataNodesHeartbeats[id] += 1
-	return &pb.HeartbeatResponse{}, nil
+	// A master that lost its tables asks the data keeper to join again
+	if _, ok := store.Node(id); !ok {
+		return &pb.HeartbeatResponse{Commands: []*pb.Command{{Type: pb.Command_REJOIN}}}, nil
+	}
+	detector.heartbeat(id)
+	store.Heartbeat(id, nodeStats{capacity: req.GetCapacity(), used: req.GetUsed(), available: req.GetAvailable(), inFlight: req.GetInFlight()})
+	if req.GetFullReport() {
+		store.BlockReport(id, req.GetBlocks())
+		scheduleUntracked(id, req.GetBlocks())
+	}
+	return &pb.HeartbeatResponse{Success: true, Commands: commands.take(id)}, nil
 }
 
 func (s *masterServer) UploadFile(ctx context.Context, req *pb.UploadFileRequest) (*pb.UploadFileResponse, error) {
-	clientPort = req.GetClientPort()
+	fileName, err := cleanPath(req.GetFileName())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFileSize() < 0 || req.GetChecksum() == "" {
+		return nil, status.Error(codes.InvalidArgument, "a file size and checksum are required")
+	}
+	if req.GetReplication() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "the replication factor cannot be negative")
+	}
+
+	// Uploading the same content again resumes the session of an interrupted upload
+	wanted := FileMetadata{FileName: fileName, Size: req.GetFileSize(), Checksum: req.GetChecksum(),
+		ContentType: contentType(fileName, req.GetContentType()), Replication: req.GetReplication()}
+	sessionId, placements, resumed, err := uploadSessions.resume(req.GetClientPort(), wanted)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if resumed {
+		fmt.Printf("Resuming the upload of %s\n", fileName)
+		return &pb.UploadFileResponse{SessionId: sessionId, Blocks: placements}, nil
+	}
+	if err := checkNewPath(fileName); err != nil {
+		return nil, err
+	}
+	if len(store.AliveNodeIds()) == 0 {
+		return nil, status.Error(codes.Unavailable, "no alive data keepers")
+	}
 
-	randomIndex := int32(rand.Intn(len(allIds)))
-	dataNodeId := allIds[randomIndex]
-	for !dataNodeLookupTable[dataNodeId].isAlive {
-		randomIndex := int(rand.Intn(len(allIds)))
-		dataNodeId = allIds[randomIndex]
+	// The placement policy picks the first data keeper of every block
+	file := splitIntoBlocks(fileName, wanted.Size, blockSize())
+	file.Checksum = wanted.Checksum
+	file.ContentType = wanted.ContentType
+	file.Replication = wanted.Replication
+	placements = make([]*pb.BlockPlacement, 0, len(file.Blocks))
+	// The usage reported by the data keepers does not include the blocks placed here yet
+	reserved := make(map[int32]int64)
+	for i, blockId := range file.Blocks {
+		offset, size := blockRange(file, i)
+		dataNodeIds := chooseNodes(1, size, reserved)
+		if len(dataNodeIds) == 0 {
+			return nil, status.Error(codes.ResourceExhausted, "no alive data keeper has room for the file")
+		}
+		reserved[dataNodeIds[0]] += size
+		node, _ := store.Node(dataNodeIds[0])
+		placements = append(placements, &pb.BlockPlacement{BlockId: blockId, Offset: offset, Size: size, GrpcAddress: node.downloadAddress})
 	}
-	nodeAddr := dataNodeLookupTable[dataNodeId].address
-	grpcAddr := dataNodeLookupTable[dataNodeId].downloadAddress
-	return &pb.UploadFileResponse{UploadAddress: nodeAddr , GrpcAddress: grpcAddr }, nil
+
+	sessionId, err = uploadSessions.start(req.GetClientPort(), file, placements)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &pb.UploadFileResponse{SessionId: sessionId, Blocks: placements}, nil
 }
 
-func notifyClient() {
-	conn, err := grpc.Dial(clientPort, grpc.WithInsecure())
+// PlaceBlock moves a block of an upload in progress to another data keeper, for a client that could not
+// reach the one chosen before. The upload of the block starts over there.
+func (s *masterServer) PlaceBlock(ctx context.Context, req *pb.PlaceBlockRequest) (*pb.BlockPlacement, error) {
+	sessionId, blockId := req.GetSessionId(), req.GetBlockId()
+	placement, err := uploadSessions.pendingBlock(sessionId, blockId)
+	if errors.Is(err, errSessionUnknown) {
+		return nil, status.Errorf(codes.Aborted, "upload session %s is unknown or timed out, start the upload again", sessionId)
+	}
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	unreachable := make(map[string]bool)
+	for _, address := range req.GetExcludedAddresses() {
+		unreachable[address] = true
+	}
+	excluded := make([]int32, 0)
+	for _, id := range store.NodeIds() {
+		if node, _ := store.Node(id); unreachable[node.downloadAddress] {
+			excluded = append(excluded, id)
+		}
+	}
+	dataNodeIds := chooseNodes(1, placement.GetSize(), nil, excluded...)
+	if len(dataNodeIds) == 0 {
+		return nil, status.Errorf(codes.Unavailable, "no other alive data keeper can take block %s", blockId)
+	}
+	node, _ := store.Node(dataNodeIds[0])
+	moved := &pb.BlockPlacement{BlockId: blockId, Offset: placement.GetOffset(), Size: placement.GetSize(), GrpcAddress: node.downloadAddress}
+	uploadSessions.move(sessionId, moved)
+	fmt.Printf("Block %s moved to Data Keeper %d\n", blockId, dataNodeIds[0])
+	return moved, nil
+}
+
+func notifyClient(clientPort string) {
+	conn, release, err := connpool.Get(clientPort)
 	if err != nil {
 		fmt.Println("Did not connect:", err)
 		return
 	}
-	defer conn.Close()
+	defer release()
 	c := cl.NewSuccessServiceClient(conn)
 	resp, err := c.ReportSuccess(context.Background(), &cl.SuccessRequest{Success: true})
 	if err != nil {
@@ -79,289 +224,590 @@ func notifyClient() {
 	fmt.Println("Client Status:", resp.GetSuccess())
 }
 
-func chooseOneRandomNode(dataNodeId int32) int32 {
-	randomIndex := int32(rand.Intn(len(allIds)))
-	nodeId := allIds[randomIndex]
-
-	for nodeId == dataNodeId || !dataNodeLookupTable[nodeId].isAlive {
-		randomIndex = int32(rand.Intn(len(allIds)))
-		nodeId = allIds[randomIndex]
+// defaultReplication reads DEFAULT_REPLICATION, the number of copies kept of files that set no factor
+func defaultReplication() int {
+	n, err := strconv.Atoi(os.Getenv("DEFAULT_REPLICATION"))
+	if err != nil || n <= 0 {
+		return 3
 	}
-	return int32(nodeId)
+	return n
 }
 
-func chooseTwoRandomNodes(dataNodeId int32) []int32 {
-	// Pick the first Id
-	nodeIds := make([]int32, 2)
-	nodeIds[0] = chooseOneRandomNode(dataNodeId)
+// replicationTarget returns how many copies of every block of file should exist: the file's own factor,
+// else the factor of the closest directory above it that sets one, else the default
+func replicationTarget(file FileMetadata) int {
+	if file.Replication > 0 {
+		return int(file.Replication)
+	}
+	for dir := path.Dir(file.FileName); ; dir = path.Dir(dir) {
+		if d, ok := store.Dir(dir); ok && d.Replication > 0 {
+			return int(d.Replication)
+		}
+		if dir == "/" {
+			return defaultReplication()
+		}
+	}
+}
 
-	// Pick the second Id
-	randomIndex := int32(rand.Intn(len(allIds)))
-	nodeId := allIds[randomIndex]
-	for nodeId == dataNodeId || nodeId == nodeIds[0] || !dataNodeLookupTable[nodeId].isAlive {
-		randomIndex := int32(rand.Intn(len(allIds)))
-		nodeId = allIds[randomIndex]
+// replicateTo asks the source data node to copy a block to the destination node
+func replicateTo(blockId string, sourceId int32, destinationId int32) (bool, error) {
+	source, _ := store.Node(sourceId)
+	destination, _ := store.Node(destinationId)
+	conn, release, err := connpool.Get(source.downloadAddress)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	c := dk.NewDataKeeperServiceClient(conn)
+	resp, err := c.ReplicateFile(context.Background(), &dk.ReplicateFileRequest{FileName: blockId, GrpcAddr: destination.downloadAddress, Checksum: blockChecksum(blockId)})
+	if err != nil {
+		return false, err
 	}
-	nodeIds[1] = int32(nodeId)
+	return resp.GetSuccess(), nil
+}
 
-	return nodeIds
+// chooseNodesToReplicate copies a block from the nodes holding it to new nodes until target copies exist
+func chooseNodesToReplicate(blockId string, target int, holders ...int32) {
+	// Copies on nodes being decommissioned are kept as sources but do not count,
+	// and copies already scheduled count as done
+	copying := commands.copying(blockId)
+	missing := target - len(activeHolders(holders)) - len(copying)
+	if missing <= 0 {
+		return
+	}
+	nodeIds := chooseNodes(missing, blockSizeOf(blockId), nil, append(copying, holders...)...)
+	if len(nodeIds) < missing {
+		fmt.Printf("[REPLICATION] Only %d alive data nodes can take block %s, %d copies are missing\n", len(nodeIds), blockId, missing)
+	}
+	// The source copies the block when it receives the command with its next heartbeat
+	for _, nodeId := range nodeIds {
+		sourceId := holders[rand.Intn(len(holders))]
+		fmt.Printf("Replicating block %s from Data node %d to Data node %d\n", blockId, sourceId, nodeId)
+		commands.copyTo(blockId, sourceId, nodeId)
+	}
 }
 
-func getAliveNodesCount() int {
-	count := 0
-	for _, node := range dataNodeLookupTable {
-		if node.isAlive {
-			count++
+// trimReplicas removes copies of a block beyond target, forgetting them before the data keepers delete them
+func trimReplicas(blockId string, target int, holders []int32) {
+	// The rebalancer drops the extra copy of a block it moves itself
+	if len(holders) <= target || isMoving(blockId) {
+		return
+	}
+	for _, nodeId := range chooseNodesToDrop(len(holders)-target, holders) {
+		if err := dropReplica(blockId, nodeId); err != nil {
+			fmt.Printf("Error deleting block %s from Data Keeper %d: %v\n", blockId, nodeId, err)
+			continue
 		}
+		fmt.Printf("[REPLICATION] Removed extra copy of block %s from Data Keeper %d\n", blockId, nodeId)
 	}
-	return count
 }
 
-func chooseNodesToReplicate(fileName string, dataNodeId int32){
-	// Check that The number of alive data nodes must be atleast 3
-	aliveCount := getAliveNodesCount()
+// dropReplica forgets the copy of a block on a data keeper and then deletes it there
+func dropReplica(blockId string, nodeId int32) error {
+	if err := raft.propose(logEntry{Op: opRemoveBlock, Replica: &BlockReplica{BlockId: blockId, DataNodeId: nodeId}}); err != nil {
+		return err
+	}
+	node, _ := store.Node(nodeId)
+	return keeperCall(node.downloadAddress, func(c dk.DataKeeperServiceClient) error {
+		_, err := c.DeleteFile(context.Background(), &dk.DeleteFileRequest{FileName: blockId})
+		return err
+	})
+}
 
-	if aliveCount == 1 {
-		fmt.Println("There is only one alive data node. Cannot replicate the file.")
-		return
-	} else if aliveCount == 2 {
-		fmt.Println("There are only two alive data nodes. Replicating the file to the other node.")
-		nodeId := chooseOneRandomNode(dataNodeId)
-		conn, err := grpc.Dial(dataNodeLookupTable[dataNodeId].downloadAddress, grpc.WithInsecure())
-		if err != nil {
-			fmt.Println("Did not connect:", err)
-			return
-		}
-		defer conn.Close()
-		c := dk.NewDataKeeperServiceClient(conn)
-		address := dataNodeLookupTable[nodeId].address
-		grpcAddr := dataNodeLookupTable[nodeId].downloadAddress
-		resp, err := c.ReplicateFile(context.Background(), &dk.ReplicateFileRequest{FileName: fileName, TcpAddr: address, GrpcAddr: grpcAddr})
-		if err != nil {
-			fmt.Println("Error sending to datakeeper:", err)
-			return
-		}
-		fmt.Println("Data Node response:", resp.GetSuccess())
-		return
-	} else {
-		nodeIds := chooseTwoRandomNodes(dataNodeId)
-		fmt.Printf("Sending the 2 nodes %d & %d to Data node %d\n", nodeIds[0], nodeIds[1], dataNodeId)
-		conn, err := grpc.Dial(dataNodeLookupTable[dataNodeId].downloadAddress, grpc.WithInsecure())
-		if err != nil {
-			fmt.Println("Did not connect:", err)
-			return
-		}
-		defer conn.Close()
-		c := dk.NewDataKeeperServiceClient(conn)
-		address := dataNodeLookupTable[nodeIds[0]].address
-		grpcAddr := dataNodeLookupTable[nodeIds[0]].downloadAddress
-		resp, err := c.ReplicateFile(context.Background(), &dk.ReplicateFileRequest{FileName: fileName, TcpAddr: address, GrpcAddr: grpcAddr})
-		if err != nil {
-			fmt.Println("Error sending to datakeeper:", err)
-			return
-		}
-		fmt.Println("First Data Node response:", resp.GetSuccess())
-	
-		address = dataNodeLookupTable[nodeIds[1]].address
-		grpcAddr = dataNodeLookupTable[nodeIds[1]].downloadAddress
-		resp, err = c.ReplicateFile(context.Background(), &dk.ReplicateFileRequest{FileName: fileName, TcpAddr: address, GrpcAddr: grpcAddr})
-		if err != nil {
-			fmt.Println("Error sending to datakeeper:", err)
-			return
-		}
-		fmt.Println("Second Data Node response:", resp.GetSuccess())
+// blockSizeOf returns the size of a block as registered by its replicas
+func blockSizeOf(blockId string) int64 {
+	for _, replica := range store.Replicas(blockId) {
+		return replica.Size
 	}
+	return 0
 }
 
-func (s *masterServer) RegisterFile(ctx context.Context, req *pb.RegisterFileRequest) (*pb.RegisterFileResponse, error) {
-	fileName := req.GetFileName()
-	fmt.Println("Saving file:", fileName)
-	
-	replicate := false
-	// If fileName is found in the fileLookupTable, then the file is being replicated
-	for _, file := range fileLookupTable {
-		if file.FileName == fileName {
-			replicate = true
-			break
+// blockChecksum returns the checksum recorded for a block by its replicas
+func blockChecksum(blockId string) string {
+	for _, replica := range store.Replicas(blockId) {
+		if replica.Checksum != "" {
+			return replica.Checksum
 		}
 	}
+	return ""
+}
+
+// blockInUse reports whether blockId belongs to a file or to an upload in progress
+func blockInUse(blockId string) bool {
+	if _, ok := store.FileOfBlock(blockId); ok {
+		return true
+	}
+	return uploadSessions.blockIds()[blockId]
+}
+
+func (s *masterServer) RegisterFile(ctx context.Context, req *pb.RegisterFileRequest) (*pb.RegisterFileResponse, error) {
+	blockId := req.GetBlockId()
+	fmt.Println("Saving block:", blockId)
 
 	dataNodeId := req.GetDataNodeId()
 	filePath := req.GetFilePath()
-	fileSize := req.GetFileSize()
-	fileMetadata := FileMetadata{FileName: fileName, DataNodeId: dataNodeId, FilePath: filePath, Size: fileSize}
-	fileLookupTable = append(fileLookupTable, fileMetadata)
+	blockSize := req.GetFileSize()
+	checksum := req.GetChecksum()
+	if checksum == "" {
+		return nil, status.Error(codes.InvalidArgument, "a block checksum is required")
+	}
+	// A replica has to match the copies already registered
+	if expected := blockChecksum(blockId); expected != "" && expected != checksum {
+		fmt.Printf("Rejecting block %s from Data Keeper %d: checksum mismatch\n", blockId, dataNodeId)
+		return nil, status.Errorf(codes.DataLoss, "checksum of block %s does not match its other replicas", blockId)
+	}
+
+	// Sessions live in the memory of the leader that opened them, so one may be gone after a failover
+	if id := req.GetSessionId(); id != "" && !uploadSessions.known(id) {
+		return nil, status.Errorf(codes.Aborted, "upload session %s is unknown or timed out, start the upload again", id)
+	}
 
-	if !replicate {
-		notifyClient()
-		chooseNodesToReplicate(fileName, dataNodeId)
+	// A copy that finishes after its file was deleted is not registered. The block stays untracked,
+	// so the data keeper is told to delete it after its next full block report.
+	if req.GetSessionId() == "" && !blockInUse(blockId) {
+		fmt.Printf("Rejecting block %s from Data Keeper %d: it belongs to no file\n", blockId, dataNodeId)
+		return nil, status.Errorf(codes.NotFound, "block %s belongs to no file", blockId)
+	}
+
+	replica := BlockReplica{BlockId: blockId, DataNodeId: dataNodeId, FilePath: filePath, Size: blockSize, Checksum: checksum}
+	if err := raft.propose(logEntry{Op: opRegisterBlock, Replica: &replica}); err != nil {
+		fmt.Println("Error logging block registration:", err)
+		return nil, err
+	}
+	commands.copied(blockId, dataNodeId)
+	// The file may have been deleted while the registration was proposed. The data keeper deletes the
+	// copy with its next heartbeat, once it is done storing it.
+	if req.GetSessionId() == "" && !blockInUse(blockId) {
+		if err := raft.propose(logEntry{Op: opRemoveBlock, Replica: &BlockReplica{BlockId: blockId, DataNodeId: dataNodeId}}); err != nil {
+			fmt.Println("Error logging block removal:", err)
+			return nil, err
+		}
+		commands.push(dataNodeId, deleteCommand(blockId))
+		return nil, status.Errorf(codes.NotFound, "block %s belongs to no file", blockId)
+	}
+
+	// Uploads carry the session handed out by UploadFile. Anything else is a replica
+	// copied between data keepers.
+	session, complete, upload := uploadSessions.blockStored(req.GetSessionId(), blockId, checksum)
+	if !upload {
+		return &pb.RegisterFileResponse{}, nil
+	}
+	go chooseNodesToReplicate(blockId, replicationTarget(session.file), dataNodeId)
+
+	// The file becomes visible once its last block is stored
+	if complete {
+		file := session.file
+		file.CreatedAt = time.Now()
+		file.ModifiedAt = file.CreatedAt
+		if err := raft.propose(logEntry{Op: opCreateFile, File: &file}); err != nil {
+			fmt.Println("Error logging file creation:", err)
+			// A block committed again must not be taken for a stored file
+			uploadSessions.drop(req.GetSessionId())
+			return nil, err
+		}
+		fmt.Printf("File %s stored in %d blocks\n", file.FileName, len(file.Blocks))
+		// Clients that wait for their uploads themselves leave the port empty
+		if session.clientPort != "" {
+			notifyClient(session.clientPort)
+		}
 	}
 	return &pb.RegisterFileResponse{}, nil
 }
 
 func (s *masterServer) DownloadFile(ctx context.Context, req *pb.DownloadFileRequest) (*pb.DownloadFileResponse, error) {
 	fileName := req.GetFileName()
-	addresses := make([]string, 0)
-	fileSize := int64(0)
-	for _, file := range fileLookupTable {
-		if file.FileName == fileName && dataNodeLookupTable[file.DataNodeId].isAlive {
-			addresses = append(addresses, dataNodeLookupTable[file.DataNodeId].downloadAddress)
-			fileSize = file.Size
-		}
+	file, ok := store.File(fileName)
+	if !ok {
+		return &pb.DownloadFileResponse{}, nil
 	}
-	return &pb.DownloadFileResponse{Addresses: addresses, FileSize: fileSize}, nil
-}
 
-func checkAliveDataNodes() {
-	for {
-		time.Sleep(2 * time.Second)
-		for id := range dataNodesHeartbeats {
-			node := dataNodeLookupTable[id]
-			if dataNodesHeartbeats[id] == 0 {
-				fmt.Printf("Node %d is dead\n", id)
-				node.isAlive = false
-			} else {
-				fmt.Printf("Node %d is alive\n", id)
-				node.isAlive = true
+	blocks := make([]*pb.BlockLocation, 0, len(file.Blocks))
+	for i, blockId := range file.Blocks {
+		offset, size := blockRange(file, i)
+		location := &pb.BlockLocation{BlockId: blockId, Offset: offset, Size: size}
+		if i < len(file.BlockChecksums) {
+			location.Checksum = file.BlockChecksums[i]
+		}
+		for _, replica := range store.Replicas(blockId) {
+			if node, ok := store.Node(replica.DataNodeId); ok && node.isAlive {
+				location.Addresses = append(location.Addresses, node.downloadAddress)
 			}
-			dataNodesHeartbeats[id] = 0
-			dataNodeLookupTable[id] = node
 		}
+		blocks = append(blocks, location)
 	}
+	return &pb.DownloadFileResponse{FileName: file.FileName, FileSize: file.Size, Blocks: blocks, Checksum: file.Checksum, ContentType: file.ContentType}, nil
 }
 
+// blockReportSlack is how long a new replica may be missing from block reports before it counts as lost
+const blockReportSlack = 5 * time.Second
+
 func Replication() {
 	for {
 		time.Sleep(10 * time.Second)
-		// Each file should exist on atleast 3 alive data nodes. If not, replicate it to one of the alive nodes.
-		// create a map with key filename and value will be an array of ids of the data nodes where the file is stored
-		fileMap := make(map[string][]int32)
-		toBeRemoved := make(map[string][]int32)
-		for _, file := range fileLookupTable {
-			// call grpc to check if file still exists
-			if dataNodeLookupTable[file.DataNodeId].isAlive {
-				conn, err := grpc.Dial(dataNodeLookupTable[file.DataNodeId].downloadAddress, grpc.WithInsecure())
-				if err != nil {
-					fmt.Println("Did not connect:", err)
-					continue
-				}
-				c := dk.NewDataKeeperServiceClient(conn)
-				resp, err := c.CheckFileExists(context.Background(), &dk.CheckFileExistsRequest{Filepath: file.FilePath})
-				if err != nil {
-					fmt.Println("Error checking file:", err)
-					continue
-				}
-				if resp.GetSuccess() {
-					fileMap[file.FileName] = append(fileMap[file.FileName], file.DataNodeId)
-				} else {
-					fmt.Printf("File %s no longer exists on Data Keeper %d\n", file.FileName, file.DataNodeId)
-					toBeRemoved[file.FileName] = append(toBeRemoved[file.FileName], file.DataNodeId)
-				}
-				conn.Close()
+		// Only the leader of the master group drives replication
+		if !raft.isLeader() {
+			continue
+		}
+		// Replicas the data keepers stopped reporting are forgotten
+		for _, replica := range store.MissingReplicas(blockReportSlack) {
+			fmt.Printf("Block %s no longer exists on Data Keeper %d\n", replica.BlockId, replica.DataNodeId)
+			err := raft.propose(logEntry{Op: opRemoveBlock, Replica: &BlockReplica{BlockId: replica.BlockId, DataNodeId: replica.DataNodeId}})
+			if err != nil {
+				fmt.Println("Error logging block removal:", err)
 			}
 		}
 
-		// remove the files that no longer exist
-		for fileName, nodes := range toBeRemoved {
-			for _, node := range nodes {
-				for i, file := range fileLookupTable {
-					if file.FileName == fileName && file.DataNodeId == node {
-						fileLookupTable = append(fileLookupTable[:i], fileLookupTable[i+1:]...)
-					}
-				}
+		// Each block should exist on as many alive data nodes as the replication factor of its file.
+		// create a map with key block id and value will be an array of ids of the data nodes where the block is stored
+		blockMap := make(map[string][]int32)
+		for _, replica := range store.AllReplicas() {
+			if node, _ := store.Node(replica.DataNodeId); node.isAlive {
+				blockMap[replica.BlockId] = append(blockMap[replica.BlockId], replica.DataNodeId)
 			}
 		}
 
-		// print fileMap
-		if len(fileMap) > 0 {
-			fmt.Println("------File Map------")
-			for key, value := range fileMap {
+		// print blockMap
+		if len(blockMap) > 0 {
+			fmt.Println("------Block Map------")
+			for key, value := range blockMap {
 				fmt.Println(key, value)
 			}
 		}
-		
-		if len(fileLookupTable) > 0 {
-			// print fileLookupTable
-			fmt.Println("------File Lookup Table------")
-			for _, file := range fileLookupTable {
-				fmt.Println(file.FileName, file.DataNodeId)
-			}
-		}
 
-		for fileName, nodes := range fileMap {
-			if len(nodes) < 3 {
-				// choose new data node ids for the fileName until the count is restored to 3, then notify the source and destination nodes to start copying
-				if len(nodes) == 2 {
-					aliveCount := getAliveNodesCount()
-					if aliveCount == 2 {
-						fmt.Print("[REPLICATION] There are only two alive data nodes.\n")
-						continue
-					}
-					randomIndex := int32(rand.Intn(len(allIds)))
-					destinationId := allIds[randomIndex]
-					for destinationId == nodes[0] || destinationId == nodes[1] || !dataNodeLookupTable[destinationId].isAlive {
-						randomIndex := int32(rand.Intn(len(allIds)))
-						destinationId = allIds[randomIndex]
-					}
-					fmt.Printf("Destination ID: %d\n", destinationId)
-					conn, err := grpc.Dial(dataNodeLookupTable[nodes[0]].downloadAddress, grpc.WithInsecure())
-					if err != nil {
-						fmt.Println("Did not connect:", err)
-						continue
-					}
-					c := dk.NewDataKeeperServiceClient(conn)
-					tcpAddr := dataNodeLookupTable[destinationId].address
-					grpcAddr := dataNodeLookupTable[destinationId].downloadAddress
-					_, err = c.ReplicateFile(context.Background(), &dk.ReplicateFileRequest{FileName: fileName, TcpAddr: tcpAddr, GrpcAddr: grpcAddr})
-					if err != nil {
-						fmt.Println("Error replicating file:", err)
-						continue
-					}
-					conn.Close()
-				} else if len(nodes) == 1 {
-					chooseNodesToReplicate(fileName, nodes[0])
-				} else {
-					fmt.Println("[REPLICATION] File does not exist on any data node")
+		// Blocks of uploads that are still running are left to the upload itself
+		for _, file := range store.FileList() {
+			target := replicationTarget(file)
+			for _, blockId := range file.Blocks {
+				nodes := blockMap[blockId]
+				active := activeHolders(nodes)
+				if len(nodes) == 0 {
+					fmt.Printf("[REPLICATION] Block %s of %s does not exist on any data node\n", blockId, file.FileName)
+				} else if len(active) < target {
+					chooseNodesToReplicate(blockId, target, nodes...)
+				} else if len(active) > target {
+					trimReplicas(blockId, target, active)
 				}
 			}
 		}
+		reportDecommissions()
+	}
+}
+
+// SetReplication changes the replication factor of a file or directory. Files inside a directory
+// follow its factor unless they set their own. A factor of 0 goes back to inheriting.
+func (s *masterServer) SetReplication(ctx context.Context, req *pb.SetReplicationRequest) (*pb.SuccessResponse, error) {
+	target, err := cleanPath(req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetReplication() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "the replication factor cannot be negative")
 	}
+	if !store.HasFile(target) && !store.HasDir(target) {
+		return nil, status.Errorf(codes.NotFound, "%s not found", target)
+	}
+	if err := raft.propose(logEntry{Op: opSetReplication, Path: target, Replication: req.GetReplication()}); err != nil {
+		fmt.Println("Error logging replication change:", err)
+		return nil, err
+	}
+	fmt.Printf("Replication factor of %s set to %d\n", target, req.GetReplication())
+	return &pb.SuccessResponse{Success: true}, nil
 }
 
 func (s *masterServer) Join(ctx context.Context, req *pb.JoinRequest) (*pb.SuccessResponse, error) {
 	id := req.GetId()
-	address := req.GetAddress()
 	grpcAddress := req.GetGrpcAddress()
-	allIds = append(allIds, id)
-	// Check if the data node is already in the lookup table
-	for dataNodeId, node := range dataNodeLookupTable {
-		if dataNodeId == id && !node.isAlive {
+	record := nodeRecord{Id: id, DownloadAddress: grpcAddress, Rack: req.GetRack(), Zone: req.GetZone()}
+	// Check if the data node is already in the lookup table. A rejoin keeps a running decommission.
+	for _, dataNodeId := range store.NodeIds() {
+		node, _ := store.Node(dataNodeId)
+		// A data keeper restarted before it was declared dead comes back on the same address
+		if dataNodeId == id && (!node.isAlive || node.downloadAddress == grpcAddress) {
 			// If the data node is already in the lookup table, update the address and set isAlive to true
-			node.address = address
-			node.downloadAddress = grpcAddress
-			node.isAlive = true
-			dataNodeLookupTable[id] = node
-			fmt.Printf("Data Node %d rejoined\n", id)
+			if err := raft.propose(logEntry{Op: opJoin, Node: &record}); err != nil {
+				fmt.Println("Error logging join:", err)
+				return nil, err
+			}
+			detector.joined(id)
+			setNodeState(id, stateAlive, "rejoined")
+			reconcileInventory(id, req.GetBlocks())
 			return &pb.SuccessResponse{Success: true}, nil
 		} else if dataNodeId == id && node.isAlive {
 			return &pb.SuccessResponse{Success: false}, nil
-		} else if node.address == address || node.downloadAddress == grpcAddress {
+		} else if node.downloadAddress == grpcAddress {
 			return &pb.SuccessResponse{Success: false}, nil
 		}
 	}
-	dataNodeData := dataNode{address: address, downloadAddress: grpcAddress, isAlive: true}
-	dataNodeLookupTable[id] = dataNodeData
-	dataNodesHeartbeats[id] = 0
-	fmt.Printf("Data Node %d joined\n", id)
+	if err := raft.propose(logEntry{Op: opJoin, Node: &record}); err != nil {
+		fmt.Println("Error logging join:", err)
+		return nil, err
+	}
+	detector.joined(id)
+	setNodeState(id, stateAlive, "joined")
+	reconcileInventory(id, req.GetBlocks())
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// ReportCorruptBlock drops a replica that failed scrubbing and copies the block again from a good replica
+func (s *masterServer) ReportCorruptBlock(ctx context.Context, req *pb.CorruptBlockRequest) (*pb.SuccessResponse, error) {
+	blockId := req.GetBlockId()
+	dataNodeId := req.GetDataNodeId()
+	fmt.Printf("Data Keeper %d reported corrupt block %s\n", dataNodeId, blockId)
+	if err := raft.propose(logEntry{Op: opRemoveBlock, Replica: &BlockReplica{BlockId: blockId, DataNodeId: dataNodeId}}); err != nil {
+		fmt.Println("Error logging block removal:", err)
+		return nil, err
+	}
+	// The other copies may have gone bad the same way, so their keepers check them now
+	for _, replica := range store.Replicas(blockId) {
+		commands.push(replica.DataNodeId, scrubCommand(blockId))
+	}
+	go repairBlock(blockId)
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// repairBlock copies a block from one of its remaining replicas to a node that does not hold it
+func repairBlock(blockId string) {
+	holders := make([]int32, 0)
+	for _, replica := range store.Replicas(blockId) {
+		if node, ok := store.Node(replica.DataNodeId); ok && node.isAlive {
+			holders = append(holders, replica.DataNodeId)
+		}
+	}
+	if len(holders) == 0 {
+		fmt.Printf("[SCRUB] Block %s has no good replica left\n", blockId)
+		return
+	}
+	destinationIds := chooseNodes(1, blockSizeOf(blockId), nil, holders...)
+	if len(destinationIds) == 0 {
+		fmt.Printf("[SCRUB] No data node available to repair block %s\n", blockId)
+		return
+	}
+	// The destination checks the copy against the recorded checksum, so a bad source cannot spread
+	success, err := replicateTo(blockId, holders[rand.Intn(len(holders))], destinationIds[0])
+	if err != nil {
+		fmt.Println("Error repairing block:", err)
+		return
+	}
+	fmt.Printf("[SCRUB] Block %s copied to Data Keeper %d: %v\n", blockId, destinationIds[0], success)
+}
+
+// keeperCall dials a data keeper and runs call against it
+func keeperCall(address string, call func(c dk.DataKeeperServiceClient) error) error {
+	conn, release, err := connpool.Get(address)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return call(dk.NewDataKeeperServiceClient(conn))
+}
+
+func (s *masterServer) DeleteFile(ctx context.Context, req *pb.DeleteFileRequest) (*pb.SuccessResponse, error) {
+	fileName, err := cleanPath(req.GetFileName())
+	if err != nil {
+		return nil, err
+	}
+	file, ok := store.File(fileName)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", fileName)
+	}
+	replicas := make([]BlockReplica, 0)
+	for _, blockId := range file.Blocks {
+		replicas = append(replicas, store.Replicas(blockId)...)
+	}
+
+	// The file disappears from the tables first, so nobody is handed a replica that is being removed
+	if err := raft.propose(logEntry{Op: opDeleteFile, File: &FileMetadata{FileName: fileName}}); err != nil {
+		fmt.Println("Error logging file deletion:", err)
+		return nil, err
+	}
+
+	// Data keepers delete the blocks when they receive the commands with their next heartbeat.
+	// Keepers that are down find the blocks untracked when they join again.
+	commands.cancelCopies(file.Blocks)
+	for _, replica := range replicas {
+		if node, ok := store.Node(replica.DataNodeId); ok && node.isAlive {
+			commands.push(replica.DataNodeId, deleteCommand(replica.BlockId))
+		}
+	}
+	fmt.Println("Deleted file:", fileName)
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// RenameFile moves a file to a new path. Blocks are stored under opaque ids, so the data keepers are not involved.
+func (s *masterServer) RenameFile(ctx context.Context, req *pb.RenameFileRequest) (*pb.SuccessResponse, error) {
+	fileName, err := cleanPath(req.GetFileName())
+	if err != nil {
+		return nil, err
+	}
+	newName, err := cleanPath(req.GetNewName())
+	if err != nil {
+		return nil, err
+	}
+	file, ok := store.File(fileName)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", fileName)
+	}
+	if err := checkNewPath(newName); err != nil {
+		return nil, err
+	}
+
+	file.FileName = newName
+	file.ModifiedAt = time.Now()
+	if err := raft.propose(logEntry{Op: opRenameFile, OldName: fileName, File: &file}); err != nil {
+		fmt.Println("Error logging file rename:", err)
+		return nil, err
+	}
+	fmt.Printf("Renamed file %s to %s\n", fileName, newName)
 	return &pb.SuccessResponse{Success: true}, nil
 }
 
+func (s *masterServer) StatFile(ctx context.Context, req *pb.StatFileRequest) (*pb.StatFileResponse, error) {
+	fileName, err := cleanPath(req.GetFileName())
+	if err != nil {
+		return nil, err
+	}
+	file, ok := store.File(fileName)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", fileName)
+	}
+
+	resp := &pb.StatFileResponse{
+		FileName:   file.FileName,
+		FileSize:   file.Size,
+		Checksum:   file.Checksum,
+		ContentType: file.ContentType,
+		Replication: int32(replicationTarget(file)),
+		CreatedAt:  file.CreatedAt.Unix(),
+		ModifiedAt: file.ModifiedAt.Unix(),
+	}
+	for i, blockId := range file.Blocks {
+		offset, size := blockRange(file, i)
+		block := &pb.BlockStat{BlockId: blockId, Offset: offset, Size: size}
+		if i < len(file.BlockChecksums) {
+			block.Checksum = file.BlockChecksums[i]
+		}
+		for _, replica := range store.Replicas(blockId) {
+			node, _ := store.Node(replica.DataNodeId)
+			block.Replicas = append(block.Replicas, &pb.ReplicaLocation{DataNodeId: replica.DataNodeId, GrpcAddress: node.downloadAddress, Alive: node.isAlive})
+		}
+		resp.Blocks = append(resp.Blocks, block)
+	}
+	return resp, nil
+}
+
+// applyCreateFile makes a fully uploaded file visible, unless its path was taken while it was uploaded
+func applyCreateFile(file FileMetadata) error {
+	return fileTableError(store.PutFile(file), file.FileName)
+}
+
+// applyRegisterBlock adds a replica to the block table
+func applyRegisterBlock(replica BlockReplica) {
+	store.AddReplica(replica)
+}
+
+// applyJoin adds a data node or updates its addresses. Liveness is not persisted,
+// so a node restored from disk stays dead until its heartbeats arrive.
+func applyJoin(record nodeRecord) {
+	store.PutNode(record)
+}
+
+// applyDeleteFile drops a file and every replica of its blocks
+func applyDeleteFile(fileName string) {
+	store.DeleteFile(fileName)
+}
+
+// applyRenameFile moves a file to its new path. The checks of the handler are made again, as
+// another change may have been committed between them and this entry.
+func applyRenameFile(oldName string, file FileMetadata) error {
+	err := store.RenameFile(oldName, file)
+	if errors.Is(err, errFileNotFound) {
+		return status.Errorf(codes.NotFound, "file %s not found", oldName)
+	}
+	return fileTableError(err, file.FileName)
+}
+
+// fileTableError turns a failed change of the file table into the error returned to the client
+func fileTableError(err error, fileName string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, errFileExists):
+		return status.Errorf(codes.AlreadyExists, "%s already exists", fileName)
+	case errors.Is(err, errDirNotFound):
+		return status.Errorf(codes.NotFound, "directory %s not found", path.Dir(fileName))
+	}
+	return err
+}
+
+// applySetReplication changes the replication factor of a file or directory
+func applySetReplication(target string, replication int32) {
+	store.SetReplication(target, replication)
+}
+
+// applyRemoveBlock drops the replica of blockId stored on dataNodeId
+func applyRemoveBlock(blockId string, dataNodeId int32) {
+	store.RemoveReplica(blockId, dataNodeId)
+}
+
+// leaderOnly serves MasterTrackerService calls on the leader only. Followers reject
+// them and point the caller at the leader through a trailer.
+func leaderOnly(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !strings.HasPrefix(info.FullMethod, "/master.MasterTrackerService/") {
+		return handler(ctx, req)
+	}
+	if raft.isLeader() {
+		resp, err := handler(ctx, req)
+		if err != errNotLeader {
+			return resp, err
+		}
+	}
+	leader := raft.leader()
+	if leader == "" || leader == raft.id {
+		return nil, status.Error(codes.Unavailable, "no leader elected yet")
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs(masters.LeaderKey, leader))
+	return nil, status.Errorf(codes.FailedPrecondition, "not the leader, current leader is %s", leader)
+}
+
 func main() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
+	// The address of this master defaults to MASTER_PORT and can be given as an argument
+	// when running several masters from MASTER_ADDRESSES
 	masterPort := os.Getenv("MASTER_PORT")
+	if len(os.Args) > 1 {
+		masterPort = os.Args[1]
+	}
+	placement = newPlacementPolicy(os.Getenv("PLACEMENT_POLICY"))
+
+	peers := make([]string, 0)
+	for _, addr := range masters.Addresses() {
+		if addr != masterPort {
+			peers = append(peers, addr)
+		}
+	}
+
+	// Restore the namespace from the snapshot and log on disk
+	dataDir := os.Getenv("MASTER_DATA_DIR")
+	if dataDir == "" {
+		dataDir = "master/data"
+	}
+	if len(peers) > 0 {
+		dataDir = filepath.Join(dataDir, strings.ReplaceAll(masterPort, ":", "_"))
+	}
+	wal, snap, err := openMetaLog(dataDir)
+	if err != nil {
+		log.Fatal("Error opening metadata log: ", err)
+	}
+	raft, err = newRaftNode(masterPort, peers, wal, snap)
+	if err != nil {
+		log.Fatal("Error starting raft: ", err)
+	}
+	// Entries after the snapshot are applied again once the group commits them
+	restored := store.Snapshot()
+	fmt.Printf("Restored %d files and %d data nodes from %s, %d log entries to replay\n", len(restored.Files), len(restored.Nodes), dataDir, len(wal.entries))
 
 	lis, err := net.Listen("tcp", masterPort)
 	if err != nil {
@@ -369,12 +815,20 @@ func main() {
 		return
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.UnaryInterceptor(leaderOnly))
 	pb.RegisterMasterTrackerServiceServer(s, &masterServer{})
+	rf.RegisterRaftServiceServer(s, raft)
 	fmt.Println("Server started. Listening on port", masterPort)
-	
+	if len(peers) > 0 {
+		fmt.Println("Master group peers:", peers)
+	}
+
+	raft.start()
 	go checkAliveDataNodes()
 	go Replication()
+	go deleteUntracked()
+	go rebalanceLoop()
+	go compactLog(raft, snapshotInterval())
 
 	if err := s.Serve(lis); err != nil {
 		fmt.Println("Failed to serve:", err)
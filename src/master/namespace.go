@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "src/grpc/master"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Directory is a node of the namespace tree. Files live in directories under their full path.
+type Directory struct {
+	Path        string    // Absolute path
+	CreatedAt   time.Time // When the directory was made
+	Replication int32     // Copies to keep of the files inside, 0 to follow the parent
+}
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// Every path component can contain only letters, numbers, dots, underscores and hyphens
+var pathComponent = regexp.MustCompile("^[a-zA-Z0-9_.-]+$")
+
+// cleanPath validates an absolute path like /projects/demo/clip and returns it in canonical form
+func cleanPath(p string) (string, error) {
+	if !strings.HasPrefix(p, "/") {
+		return "", status.Errorf(codes.InvalidArgument, "path %q must start with /", p)
+	}
+	p = path.Clean(p)
+	if p == "/" {
+		return p, nil
+	}
+	for _, part := range strings.Split(p[1:], "/") {
+		if !pathComponent.MatchString(part) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid path component %q", part)
+		}
+	}
+	return p, nil
+}
+
+// contentType returns the MIME type given by the client, or the one registered for the extension of fileName
+func contentType(fileName string, given string) string {
+	if given != "" {
+		return given
+	}
+	if byExtension := mime.TypeByExtension(path.Ext(fileName)); byExtension != "" {
+		return byExtension
+	}
+	return "application/octet-stream"
+}
+
+// checkNewPath makes sure a file or directory can be created at p
+func checkNewPath(p string) error {
+	if p == "/" || store.HasDir(p) || store.HasFile(p) {
+		return status.Errorf(codes.AlreadyExists, "%s already exists", p)
+	}
+	if uploadSessions.uploading(p) {
+		return status.Errorf(codes.FailedPrecondition, "%s is locked by an upload in progress", p)
+	}
+	if parent := path.Dir(p); !store.HasDir(parent) {
+		return status.Errorf(codes.NotFound, "directory %s not found", parent)
+	}
+	return nil
+}
+
+func (s *masterServer) Mkdir(ctx context.Context, req *pb.MkdirRequest) (*pb.SuccessResponse, error) {
+	dirPath, err := cleanPath(req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories to create, outermost first
+	missing := []string{dirPath}
+	if req.GetParents() {
+		if store.HasDir(dirPath) {
+			return &pb.SuccessResponse{Success: true}, nil
+		}
+		for parent := path.Dir(dirPath); !store.HasDir(parent); parent = path.Dir(parent) {
+			missing = append([]string{parent}, missing...)
+		}
+	}
+	for _, p := range missing {
+		if err := checkNewPath(p); err != nil {
+			return nil, err
+		}
+		err := raft.propose(logEntry{Op: opMkdir, Dir: &Directory{Path: p, CreatedAt: time.Now()}})
+		// With parents set, a directory created by someone else in the meantime is fine
+		if req.GetParents() && status.Code(err) == codes.AlreadyExists && store.HasDir(p) {
+			continue
+		}
+		if err != nil {
+			fmt.Println("Error logging mkdir:", err)
+			return nil, err
+		}
+		fmt.Println("Created directory:", p)
+	}
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+func (s *masterServer) Rmdir(ctx context.Context, req *pb.RmdirRequest) (*pb.SuccessResponse, error) {
+	dirPath, err := cleanPath(req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if dirPath == "/" {
+		return nil, status.Error(codes.InvalidArgument, "cannot remove the root directory")
+	}
+	if !store.HasDir(dirPath) {
+		return nil, status.Errorf(codes.NotFound, "directory %s not found", dirPath)
+	}
+	if dirs, files := store.List(dirPath, false); len(dirs) > 0 || len(files) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory %s is not empty", dirPath)
+	}
+	if err := raft.propose(logEntry{Op: opRmdir, Dir: &Directory{Path: dirPath}}); err != nil {
+		fmt.Println("Error logging rmdir:", err)
+		return nil, err
+	}
+	fmt.Println("Removed directory:", dirPath)
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// ListFiles returns the entries of a directory in path order, one page at a time
+func (s *masterServer) ListFiles(ctx context.Context, req *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	dirPath, err := cleanPath(req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	if !store.HasDir(dirPath) {
+		return nil, status.Errorf(codes.NotFound, "directory %s not found", dirPath)
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	// Merge the sorted directory and file lists, skipping everything up to the page token
+	dirs, files := store.List(dirPath, req.GetRecursive())
+	entries := make([]*pb.FileEntry, 0, pageSize)
+	i, j := 0, 0
+	for len(entries) <= pageSize && (i < len(dirs) || j < len(files)) {
+		var entry *pb.FileEntry
+		if j == len(files) || (i < len(dirs) && dirs[i].Path < files[j].FileName) {
+			entry = &pb.FileEntry{Path: dirs[i].Path, IsDir: true, ModifiedAt: dirs[i].CreatedAt.Unix()}
+			i++
+		} else {
+			entry = &pb.FileEntry{Path: files[j].FileName, Size: files[j].Size, ModifiedAt: files[j].ModifiedAt.Unix(), ContentType: files[j].ContentType}
+			j++
+		}
+		if entry.Path > req.GetPageToken() {
+			entries = append(entries, entry)
+		}
+	}
+
+	resp := &pb.ListFilesResponse{Entries: entries}
+	if len(entries) > pageSize {
+		resp.Entries = entries[:pageSize]
+		resp.NextPageToken = entries[pageSize-1].Path
+	}
+	return resp, nil
+}
+
+// applyMkdir adds a directory to the namespace unless its path was taken in the meantime
+func applyMkdir(dir Directory) error {
+	return fileTableError(store.PutDir(dir), dir.Path)
+}
+
+// applyRmdir removes a directory from the namespace if it is still there and empty
+func applyRmdir(dirPath string) error {
+	err := store.RemoveDir(dirPath)
+	switch {
+	case errors.Is(err, errDirNotFound):
+		return status.Errorf(codes.NotFound, "directory %s not found", dirPath)
+	case errors.Is(err, errDirNotEmpty):
+		return status.Errorf(codes.FailedPrecondition, "directory %s is not empty", dirPath)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "src/grpc/master"
+)
+
+func TestCleanPath(t *testing.T) {
+	for _, c := range []struct {
+		in, want string
+		valid    bool
+	}{
+		{"/", "/", true},
+		{"/a/b", "/a/b", true},
+		{"//a//b/", "/a/b", true},
+		{"/a/./b/../c", "/a/c", true},
+		{"/../a", "/a", true},
+		{"a/b", "", false},
+		{"", "", false},
+		{"/a b", "", false},
+		{"/a/ü", "", false},
+	} {
+		got, err := cleanPath(c.in)
+		if (err == nil) != c.valid || got != c.want {
+			t.Errorf("cleanPath(%q) = %q, %v; want %q, valid %v", c.in, got, err, c.want, c.valid)
+		}
+	}
+}
+
+// listAll pages through a directory and returns every path listed
+func listAll(t *testing.T, dirPath string, recursive bool, pageSize int32) ([]string, int) {
+	t.Helper()
+	var paths []string
+	pages := 0
+	token := ""
+	for {
+		resp, err := (&masterServer{}).ListFiles(context.Background(), &pb.ListFilesRequest{Path: dirPath, Recursive: recursive, PageSize: pageSize, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, entry := range resp.GetEntries() {
+			paths = append(paths, entry.GetPath())
+		}
+		if token = resp.GetNextPageToken(); token == "" {
+			return paths, pages
+		}
+	}
+}
+
+func TestListFilesPages(t *testing.T) {
+	useNodes(t, randomPolicy{})
+	for _, dir := range []string{"/d", "/d/e", "/z"} {
+		store.PutDir(Directory{Path: dir})
+	}
+	for _, file := range []string{"/d/a", "/d/f", "/d/e/x", "/top"} {
+		store.PutFile(testFile(file, file+"-b0"))
+	}
+
+	for _, c := range []struct {
+		dir       string
+		recursive bool
+		pageSize  int32
+		want      string
+		pages     int
+	}{
+		{"/d", false, 0, "/d/a /d/e /d/f", 1},
+		{"/d", false, 2, "/d/a /d/e /d/f", 2},
+		{"/d", false, 3, "/d/a /d/e /d/f", 1},
+		{"/d", true, 1, "/d/a /d/e /d/e/x /d/f", 4},
+		{"/", false, 2, "/d /top /z", 2},
+		{"/z", false, 2, "", 1},
+	} {
+		paths, pages := listAll(t, c.dir, c.recursive, c.pageSize)
+		if got := strings.Join(paths, " "); got != c.want || pages != c.pages {
+			t.Errorf("list %s (recursive %v) by %d: %q in %d pages, want %q in %d", c.dir, c.recursive, c.pageSize, got, pages, c.want, c.pages)
+		}
+	}
+
+	// A token from an entry that was removed meanwhile still continues after it
+	resp, err := (&masterServer{}).ListFiles(context.Background(), &pb.ListFilesRequest{Path: "/d", PageToken: "/d/b"})
+	if err != nil || len(resp.GetEntries()) != 2 || resp.GetEntries()[0].GetPath() != "/d/e" {
+		t.Errorf("listing after a removed entry: %v, %v", resp.GetEntries(), err)
+	}
+	if _, err := (&masterServer{}).ListFiles(context.Background(), &pb.ListFilesRequest{Path: "/missing"}); err == nil {
+		t.Error("listed a missing directory")
+	}
+}
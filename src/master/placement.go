@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// placementNode is what a placement policy knows about a data node
+type placementNode struct {
+	id   int32
+	rack string
+	zone string
+	used int64 // bytes of blocks stored on the node
+}
+
+// failureDomain identifies the rack of a node. Racks are only unique within a zone.
+func (n placementNode) failureDomain() string {
+	return n.zone + "/" + n.rack
+}
+
+// PlacementPolicy decides which data nodes receive the copies of a block
+type PlacementPolicy interface {
+	// Choose picks up to count nodes out of candidates for new copies of a block already stored on holders
+	Choose(candidates []placementNode, holders []placementNode, count int) []int32
+	// Trim picks count nodes out of holders whose copies of a block can be dropped
+	Trim(holders []placementNode, count int) []int32
+}
+
+// newPlacementPolicy returns the policy named by PLACEMENT_POLICY: random (the default), least-used, round-robin or rack-aware
+func newPlacementPolicy(name string) PlacementPolicy {
+	switch name {
+	case "", "random":
+		return randomPolicy{}
+	case "least-used":
+		return leastUsedPolicy{}
+	case "round-robin":
+		return &roundRobinPolicy{}
+	case "rack-aware":
+		return rackAwarePolicy{}
+	}
+	fmt.Printf("Unknown placement policy %q, using random\n", name)
+	return randomPolicy{}
+}
+
+// placement is set from the environment when the master starts
+var placement PlacementPolicy = randomPolicy{}
+
+func nodeIds(nodes []placementNode) []int32 {
+	ids := make([]int32, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.id)
+	}
+	return ids
+}
+
+func shuffled(nodes []placementNode) []placementNode {
+	nodes = append([]placementNode(nil), nodes...)
+	rand.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+	return nodes
+}
+
+// randomPolicy spreads copies uniformly at random
+type randomPolicy struct{}
+
+func (randomPolicy) Choose(candidates []placementNode, holders []placementNode, count int) []int32 {
+	return nodeIds(shuffled(candidates)[:min(count, len(candidates))])
+}
+
+func (randomPolicy) Trim(holders []placementNode, count int) []int32 {
+	return nodeIds(shuffled(holders)[:min(count, len(holders))])
+}
+
+// leastUsedPolicy fills the emptiest disks first and frees the fullest ones first
+type leastUsedPolicy struct{}
+
+func (leastUsedPolicy) Choose(candidates []placementNode, holders []placementNode, count int) []int32 {
+	nodes := shuffled(candidates)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].used < nodes[j].used })
+	return nodeIds(nodes[:min(count, len(nodes))])
+}
+
+func (leastUsedPolicy) Trim(holders []placementNode, count int) []int32 {
+	nodes := shuffled(holders)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].used > nodes[j].used })
+	return nodeIds(nodes[:min(count, len(nodes))])
+}
+
+// roundRobinPolicy hands out nodes in id order, continuing where the previous choice stopped
+type roundRobinPolicy struct {
+	mu   sync.Mutex
+	last int32
+}
+
+func (p *roundRobinPolicy) Choose(candidates []placementNode, holders []placementNode, count int) []int32 {
+	nodes := append([]placementNode(nil), candidates...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].id < nodes[j].id })
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Start with the first node after the one chosen last
+	start := sort.Search(len(nodes), func(i int) bool { return nodes[i].id > p.last })
+	chosen := make([]int32, 0, count)
+	for i := 0; i < len(nodes) && len(chosen) < count; i++ {
+		node := nodes[(start+i)%len(nodes)]
+		chosen = append(chosen, node.id)
+		p.last = node.id
+	}
+	return chosen
+}
+
+func (p *roundRobinPolicy) Trim(holders []placementNode, count int) []int32 {
+	return randomPolicy{}.Trim(holders, count)
+}
+
+// rackAwarePolicy keeps the copies of a block in as many zones, and then racks, as possible,
+// so losing one rack or zone never loses every copy
+type rackAwarePolicy struct{}
+
+func (rackAwarePolicy) Choose(candidates []placementNode, holders []placementNode, count int) []int32 {
+	zones := make(map[string]int)
+	racks := make(map[string]int)
+	for _, node := range holders {
+		zones[node.zone]++
+		racks[node.failureDomain()]++
+	}
+	remaining := shuffled(candidates)
+	chosen := make([]int32, 0, count)
+	for len(chosen) < count && len(remaining) > 0 {
+		// The least represented zone wins, then the least represented rack, then the emptiest disk
+		best := 0
+		for i, node := range remaining {
+			b := remaining[best]
+			if zones[node.zone] != zones[b.zone] {
+				if zones[node.zone] < zones[b.zone] {
+					best = i
+				}
+			} else if racks[node.failureDomain()] != racks[b.failureDomain()] {
+				if racks[node.failureDomain()] < racks[b.failureDomain()] {
+					best = i
+				}
+			} else if node.used < b.used {
+				best = i
+			}
+		}
+		node := remaining[best]
+		chosen = append(chosen, node.id)
+		zones[node.zone]++
+		racks[node.failureDomain()]++
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return chosen
+}
+
+func (rackAwarePolicy) Trim(holders []placementNode, count int) []int32 {
+	zones := make(map[string]int)
+	racks := make(map[string]int)
+	for _, node := range holders {
+		zones[node.zone]++
+		racks[node.failureDomain()]++
+	}
+	remaining := shuffled(holders)
+	dropped := make([]int32, 0, count)
+	for len(dropped) < count && len(remaining) > 0 {
+		// Drop from the most represented zone and rack first, keeping the spread
+		worst := 0
+		for i, node := range remaining {
+			w := remaining[worst]
+			if zones[node.zone] != zones[w.zone] {
+				if zones[node.zone] > zones[w.zone] {
+					worst = i
+				}
+			} else if racks[node.failureDomain()] != racks[w.failureDomain()] {
+				if racks[node.failureDomain()] > racks[w.failureDomain()] {
+					worst = i
+				}
+			} else if node.used > w.used {
+				worst = i
+			}
+		}
+		node := remaining[worst]
+		dropped = append(dropped, node.id)
+		zones[node.zone]--
+		racks[node.failureDomain()]--
+		remaining = append(remaining[:worst], remaining[worst+1:]...)
+	}
+	return dropped
+}
+
+// placementNodes describes the given data nodes to the placement policy, counting the bytes
+// reserved on them as used
+func placementNodes(ids []int32, reserved map[int32]int64) []placementNode {
+	nodes := make([]placementNode, 0, len(ids))
+	for _, id := range ids {
+		node, _ := store.Node(id)
+		used := node.used + reserved[id]
+		if node.capacity == 0 {
+			// No load reported yet, so count the blocks registered on the node
+			for _, replica := range store.ReplicasOnNode(id) {
+				used += replica.Size
+			}
+		}
+		nodes = append(nodes, placementNode{id: id, rack: node.rack, zone: node.zone, used: used})
+	}
+	return nodes
+}
+
+// chooseNodes asks the placement policy for count alive data nodes that do not hold the block yet,
+// are neither suspect nor being decommissioned and have room for size more bytes on top of the bytes
+// reserved on them. Reserved bytes are those of earlier blocks placed since the last heartbeat, may be nil.
+func chooseNodes(count int, size int64, reserved map[int32]int64, holders ...int32) []int32 {
+	candidates := make([]int32, 0)
+	for _, id := range store.AliveNodeIds() {
+		if node, _ := store.Node(id); node.state != stateAlive || node.decommissioning || (node.capacity > 0 && node.available-reserved[id] < size) {
+			continue
+		}
+		excluded := false
+		for _, holder := range holders {
+			if id == holder {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			candidates = append(candidates, id)
+		}
+	}
+	return placement.Choose(placementNodes(candidates, reserved), placementNodes(holders, reserved), count)
+}
+
+// chooseNodesToDrop asks the placement policy which count of the holders should lose their copy
+func chooseNodesToDrop(count int, holders []int32) []int32 {
+	return placement.Trim(placementNodes(holders, nil), count)
+}
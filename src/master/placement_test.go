@@ -0,0 +1,134 @@
+package main
+
+import "testing"
+
+// useNodes replaces the tables and placement policy of the master with alive data nodes of the given free space
+func useNodes(t *testing.T, policy PlacementPolicy, available ...int64) {
+	t.Helper()
+	oldStore, oldPlacement := store, placement
+	t.Cleanup(func() { store, placement = oldStore, oldPlacement })
+	store, placement = newMemoryStore(), policy
+	for i, free := range available {
+		id := int32(i + 1)
+		store.PutNode(nodeRecord{Id: id})
+		store.SetState(id, stateAlive)
+		store.Heartbeat(id, nodeStats{capacity: 100, used: 100 - free, available: free})
+	}
+}
+
+func TestChooseNodesCountsReservedBytes(t *testing.T) {
+	useNodes(t, leastUsedPolicy{}, 50, 30)
+	reserved := make(map[int32]int64)
+	placed := make(map[int32]int64)
+	for i := 0; i < 8; i++ {
+		ids := chooseNodes(1, 10, reserved)
+		if len(ids) != 1 {
+			t.Fatalf("block %d not placed", i)
+		}
+		reserved[ids[0]] += 10
+		placed[ids[0]] += 10
+	}
+	// The blocks fill both nodes instead of all going to the emptiest one
+	if placed[1] != 50 || placed[2] != 30 {
+		t.Errorf("placed %v, want 50 bytes on node 1 and 30 on node 2", placed)
+	}
+	if ids := chooseNodes(1, 10, reserved); len(ids) != 0 {
+		t.Errorf("placed a block on node %v beyond its free space", ids)
+	}
+}
+
+func TestPlacementPolicies(t *testing.T) {
+	nodes := []placementNode{
+		{id: 1, zone: "z1", rack: "r1", used: 50},
+		{id: 2, zone: "z1", rack: "r1", used: 10},
+		{id: 3, zone: "z1", rack: "r2", used: 30},
+		{id: 4, zone: "z2", rack: "r1", used: 40},
+	}
+	byId := func(ids ...int32) []placementNode {
+		picked := make([]placementNode, 0, len(ids))
+		for _, id := range ids {
+			picked = append(picked, nodes[id-1])
+		}
+		return picked
+	}
+	for _, c := range []struct {
+		name       string
+		policy     PlacementPolicy
+		candidates []placementNode
+		holders    []placementNode
+		count      int
+		want       []int32
+	}{
+		{"least-used picks the emptiest", leastUsedPolicy{}, nodes, nil, 2, []int32{2, 3}},
+		{"least-used with fewer candidates than copies", leastUsedPolicy{}, byId(1), nil, 2, []int32{1}},
+		{"round-robin starts at the lowest id", &roundRobinPolicy{}, nodes, nil, 3, []int32{1, 2, 3}},
+		{"round-robin wraps around", &roundRobinPolicy{last: 3}, nodes, nil, 2, []int32{4, 1}},
+		{"rack-aware prefers another zone", rackAwarePolicy{}, byId(2, 3, 4), byId(1), 1, []int32{4}},
+		{"rack-aware then another rack", rackAwarePolicy{}, byId(2, 3), byId(1, 4), 1, []int32{3}},
+		{"rack-aware spreads new copies", rackAwarePolicy{}, nodes, nil, 3, []int32{2, 4, 3}},
+		{"rack-aware then the emptiest disk", rackAwarePolicy{}, byId(1, 2), byId(3, 4), 1, []int32{2}},
+	} {
+		got := c.policy.Choose(c.candidates, c.holders, c.count)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: chose %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: chose %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPlacementTrim(t *testing.T) {
+	holders := []placementNode{
+		{id: 1, zone: "z1", rack: "r1", used: 50},
+		{id: 2, zone: "z1", rack: "r1", used: 10},
+		{id: 3, zone: "z1", rack: "r2", used: 30},
+		{id: 4, zone: "z2", rack: "r1", used: 40},
+	}
+	for _, c := range []struct {
+		name   string
+		policy PlacementPolicy
+		count  int
+		want   []int32
+	}{
+		{"least-used frees the fullest", leastUsedPolicy{}, 2, []int32{1, 4}},
+		// Zone z1 and its rack r1 hold the most copies; node 1 is the fuller of the two there
+		{"rack-aware keeps the spread", rackAwarePolicy{}, 1, []int32{1}},
+		{"rack-aware keeps one copy per zone", rackAwarePolicy{}, 2, []int32{1, 3}},
+	} {
+		got := c.policy.Trim(holders, c.count)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: dropped %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: dropped %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+	if dropped := (randomPolicy{}).Trim(holders, 5); len(dropped) != len(holders) {
+		t.Errorf("random policy dropped %v, want every holder once", dropped)
+	}
+}
+
+func TestNewPlacementPolicy(t *testing.T) {
+	for name, want := range map[string]PlacementPolicy{
+		"":            randomPolicy{},
+		"least-used":  leastUsedPolicy{},
+		"rack-aware":  rackAwarePolicy{},
+		"no-such-one": randomPolicy{},
+	} {
+		if got := newPlacementPolicy(name); got != want {
+			t.Errorf("policy %q is %T, want %T", name, got, want)
+		}
+	}
+	if _, ok := newPlacementPolicy("round-robin").(*roundRobinPolicy); !ok {
+		t.Error("round-robin policy not chosen")
+	}
+}
@@ -0,0 +1,595 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	rf "src/grpc/raft"
+
+	"src/connpool"
+)
+
+const (
+	heartbeatInterval  = 100 * time.Millisecond
+	minElectionTimeout = 500 * time.Millisecond
+	maxElectionTimeout = 1000 * time.Millisecond
+	proposeTimeout     = 5 * time.Second
+	maxEntriesPerSend  = 256
+)
+
+type raftRole int
+
+const (
+	follower raftRole = iota
+	candidate
+	leader
+)
+
+var errNotLeader = errors.New("not the leader")
+
+// raftNode replicates the metadata log across the master group and applies
+// committed entries to the lookup tables
+type raftNode struct {
+	rf.UnimplementedRaftServiceServer
+
+	mu      sync.Mutex
+	applyMu sync.Mutex // held while the tables are being changed by committed entries
+	cond    *sync.Cond
+
+	id    string // own address
+	peers []string
+	log   *metaLog
+
+	role        raftRole
+	currentTerm uint64
+	votedFor    string
+	leaderId    string
+	lastContact time.Time
+	timeout     time.Duration
+
+	commitIndex uint64
+	lastApplied uint64
+	nextIndex   map[string]uint64
+	matchIndex  map[string]uint64
+	triggers    map[string]chan struct{}
+	waiters     map[uint64]waiter
+}
+
+// waiter is a proposal waiting for its entry to be applied
+type waiter struct {
+	term uint64
+	done chan error
+}
+
+// newRaftNode restores the node from disk. The tables are reset to the snapshot;
+// the entries after it are applied again once they are known to be committed.
+func newRaftNode(id string, peers []string, l *metaLog, snap *snapshot) (*raftNode, error) {
+	state, err := l.loadState()
+	if err != nil {
+		return nil, err
+	}
+	r := &raftNode{
+		id:          id,
+		peers:       peers,
+		log:         l,
+		currentTerm: state.Term,
+		votedFor:    state.VotedFor,
+		lastContact: time.Now(),
+		timeout:     randomElectionTimeout(),
+		nextIndex:   make(map[string]uint64),
+		matchIndex:  make(map[string]uint64),
+		triggers:    make(map[string]chan struct{}),
+		waiters:     make(map[uint64]waiter),
+	}
+	r.cond = sync.NewCond(&r.mu)
+	if snap != nil {
+		restoreSnapshot(*snap)
+		r.commitIndex = snap.Index
+		r.lastApplied = snap.Index
+	}
+	for _, peer := range peers {
+		r.triggers[peer] = make(chan struct{}, 1)
+	}
+	return r, nil
+}
+
+// peerCall runs call against another master over a pooled connection
+func peerCall(peer string, call func(c rf.RaftServiceClient) error) error {
+	conn, release, err := connpool.Get(peer)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return call(rf.NewRaftServiceClient(conn))
+}
+
+func randomElectionTimeout() time.Duration {
+	return minElectionTimeout + time.Duration(rand.Int63n(int64(maxElectionTimeout-minElectionTimeout)))
+}
+
+// start launches the election timer, the replication loops and the applier
+func (r *raftNode) start() {
+	go r.ticker()
+	go r.applier()
+	for _, peer := range r.peers {
+		go r.peerLoop(peer)
+	}
+}
+
+func (r *raftNode) isLeader() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.role == leader
+}
+
+// caughtUp reports whether this node leads and has applied every entry committed before its term,
+// so that its tables are complete enough to decide which blocks are no longer tracked
+func (r *raftNode) caughtUp() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.role == leader && r.log.termAt(r.lastApplied) == r.currentTerm
+}
+
+// leader returns the address of the current leader, or "" if none is known
+func (r *raftNode) leader() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leaderId
+}
+
+// propose appends the entry to the log and waits until it has been committed and applied
+func (r *raftNode) propose(entry logEntry) error {
+	r.mu.Lock()
+	if r.role != leader {
+		r.mu.Unlock()
+		return errNotLeader
+	}
+	entry.Index = r.log.lastIndex() + 1
+	entry.Term = r.currentTerm
+	if err := r.log.append(entry); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	done := make(chan error, 1)
+	r.waiters[entry.Index] = waiter{term: entry.Term, done: done}
+	r.advanceCommit()
+	r.triggerAll()
+	r.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(proposeTimeout):
+		r.mu.Lock()
+		delete(r.waiters, entry.Index)
+		r.mu.Unlock()
+		return fmt.Errorf("timed out waiting for entry %d to commit", entry.Index)
+	}
+}
+
+func (r *raftNode) ticker() {
+	for {
+		time.Sleep(heartbeatInterval / 2)
+		r.mu.Lock()
+		if r.role != leader && time.Since(r.lastContact) > r.timeout {
+			r.startElection()
+		}
+		r.mu.Unlock()
+	}
+}
+
+// startElection must be called with r.mu held
+func (r *raftNode) startElection() {
+	r.role = candidate
+	r.currentTerm++
+	r.votedFor = r.id
+	r.leaderId = ""
+	r.lastContact = time.Now()
+	r.timeout = randomElectionTimeout()
+	r.persistState()
+	fmt.Printf("[RAFT] Starting election for term %d\n", r.currentTerm)
+
+	term := r.currentTerm
+	req := &rf.RequestVoteRequest{
+		Term:         term,
+		CandidateId:  r.id,
+		LastLogIndex: r.log.lastIndex(),
+		LastLogTerm:  r.log.lastTerm(),
+	}
+	votes := 1
+	if votes > (len(r.peers)+1)/2 {
+		r.becomeLeader()
+		return
+	}
+	for _, peer := range r.peers {
+		go func(peer string) {
+			ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval*2)
+			defer cancel()
+			var resp *rf.RequestVoteResponse
+			err := peerCall(peer, func(c rf.RaftServiceClient) (err error) {
+				resp, err = c.RequestVote(ctx, req)
+				return err
+			})
+			if err != nil {
+				return
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if resp.GetTerm() > r.currentTerm {
+				r.stepDown(resp.GetTerm())
+				return
+			}
+			if r.role != candidate || r.currentTerm != term || !resp.GetVoteGranted() {
+				return
+			}
+			votes++
+			if votes > (len(r.peers)+1)/2 {
+				r.becomeLeader()
+			}
+		}(peer)
+	}
+}
+
+// becomeLeader must be called with r.mu held
+func (r *raftNode) becomeLeader() {
+	r.role = leader
+	r.leaderId = r.id
+	fmt.Printf("[RAFT] Became leader for term %d\n", r.currentTerm)
+	for _, peer := range r.peers {
+		r.nextIndex[peer] = r.log.lastIndex() + 1
+		r.matchIndex[peer] = 0
+	}
+	// Entries from earlier terms only commit once an entry of the current term does
+	noop := logEntry{Index: r.log.lastIndex() + 1, Term: r.currentTerm, Op: opNoop}
+	if err := r.log.append(noop); err != nil {
+		fmt.Println("[RAFT] Error appending to log:", err)
+	}
+	r.advanceCommit()
+	r.triggerAll()
+}
+
+// stepDown must be called with r.mu held
+func (r *raftNode) stepDown(term uint64) {
+	if term > r.currentTerm {
+		r.currentTerm = term
+		r.votedFor = ""
+		r.persistState()
+	}
+	if r.role == leader {
+		fmt.Printf("[RAFT] Stepping down in term %d\n", r.currentTerm)
+	}
+	r.role = follower
+}
+
+func (r *raftNode) persistState() {
+	if err := r.log.saveState(hardState{Term: r.currentTerm, VotedFor: r.votedFor}); err != nil {
+		fmt.Println("[RAFT] Error saving state:", err)
+	}
+}
+
+func (r *raftNode) triggerAll() {
+	for _, trigger := range r.triggers {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// peerLoop keeps one follower up to date, sending heartbeats when there is nothing new
+func (r *raftNode) peerLoop(peer string) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.triggers[peer]:
+		}
+		if r.isLeader() {
+			r.replicateTo(peer)
+		}
+	}
+}
+
+func (r *raftNode) replicateTo(peer string) {
+	r.mu.Lock()
+	if r.role != leader {
+		r.mu.Unlock()
+		return
+	}
+	term := r.currentTerm
+	next := r.nextIndex[peer]
+	if next <= r.log.snapIndex {
+		r.mu.Unlock()
+		r.sendSnapshot(peer, term)
+		return
+	}
+	prevIndex := next - 1
+	entries := r.log.slice(next, maxEntriesPerSend)
+	req := &rf.AppendEntriesRequest{
+		Term:         term,
+		LeaderId:     r.id,
+		PrevLogIndex: prevIndex,
+		PrevLogTerm:  r.log.termAt(prevIndex),
+		LeaderCommit: r.commitIndex,
+	}
+	for _, entry := range entries {
+		command, _ := json.Marshal(entry)
+		req.Entries = append(req.Entries, &rf.LogEntry{Index: entry.Index, Term: entry.Term, Command: command})
+	}
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval*5)
+	defer cancel()
+	var resp *rf.AppendEntriesResponse
+	err := peerCall(peer, func(c rf.RaftServiceClient) (err error) {
+		resp, err = c.AppendEntries(ctx, req)
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resp.GetTerm() > r.currentTerm {
+		r.stepDown(resp.GetTerm())
+		return
+	}
+	if r.role != leader || r.currentTerm != term {
+		return
+	}
+	if resp.GetSuccess() {
+		match := prevIndex + uint64(len(entries))
+		if match > r.matchIndex[peer] {
+			r.matchIndex[peer] = match
+		}
+		r.nextIndex[peer] = r.matchIndex[peer] + 1
+		r.advanceCommit()
+		if r.nextIndex[peer] <= r.log.lastIndex() {
+			r.triggerPeer(peer)
+		}
+		return
+	}
+	if resp.GetConflictIndex() > 0 && resp.GetConflictIndex() < next {
+		r.nextIndex[peer] = resp.GetConflictIndex()
+	} else if next > 1 {
+		r.nextIndex[peer] = next - 1
+	}
+	r.triggerPeer(peer)
+}
+
+func (r *raftNode) triggerPeer(peer string) {
+	select {
+	case r.triggers[peer] <- struct{}{}:
+	default:
+	}
+}
+
+func (r *raftNode) sendSnapshot(peer string, term uint64) {
+	data, err := os.ReadFile(r.log.snapshotPath())
+	if err != nil {
+		fmt.Println("[RAFT] Error reading snapshot:", err)
+		return
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		fmt.Println("[RAFT] Error reading snapshot:", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), proposeTimeout)
+	defer cancel()
+	var resp *rf.InstallSnapshotResponse
+	err = peerCall(peer, func(c rf.RaftServiceClient) (err error) {
+		resp, err = c.InstallSnapshot(ctx, &rf.InstallSnapshotRequest{
+			Term:              term,
+			LeaderId:          r.id,
+			LastIncludedIndex: snap.Index,
+			LastIncludedTerm:  snap.Term,
+			Data:              data,
+		})
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resp.GetTerm() > r.currentTerm {
+		r.stepDown(resp.GetTerm())
+		return
+	}
+	if r.role == leader && r.currentTerm == term {
+		r.matchIndex[peer] = snap.Index
+		r.nextIndex[peer] = snap.Index + 1
+	}
+}
+
+// advanceCommit must be called with r.mu held
+func (r *raftNode) advanceCommit() {
+	for index := r.log.lastIndex(); index > r.commitIndex; index-- {
+		if r.log.termAt(index) != r.currentTerm {
+			break
+		}
+		count := 1
+		for _, peer := range r.peers {
+			if r.matchIndex[peer] >= index {
+				count++
+			}
+		}
+		if count > (len(r.peers)+1)/2 {
+			r.commitIndex = index
+			r.cond.Broadcast()
+			return
+		}
+	}
+}
+
+// applier applies committed entries to the tables in log order
+func (r *raftNode) applier() {
+	for {
+		r.mu.Lock()
+		for r.lastApplied >= r.commitIndex {
+			r.cond.Wait()
+		}
+		r.mu.Unlock()
+
+		// Read the entries again under the apply lock in case a snapshot was installed meanwhile
+		r.applyMu.Lock()
+		r.mu.Lock()
+		entries := r.log.slice(r.lastApplied+1, int(r.commitIndex-r.lastApplied))
+		r.mu.Unlock()
+		results := make([]error, len(entries))
+		for i, entry := range entries {
+			results[i] = applyEntry(entry)
+		}
+		r.mu.Lock()
+		for i, entry := range entries {
+			if entry.Index > r.lastApplied {
+				r.lastApplied = entry.Index
+			}
+			if w, ok := r.waiters[entry.Index]; ok {
+				if w.term == entry.Term {
+					w.done <- results[i]
+				} else {
+					w.done <- errNotLeader
+				}
+				delete(r.waiters, entry.Index)
+			}
+		}
+		r.mu.Unlock()
+		r.applyMu.Unlock()
+	}
+}
+
+// compact snapshots the tables as of the last applied entry and trims the log
+func (r *raftNode) compact() error {
+	r.applyMu.Lock()
+	defer r.applyMu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lastApplied <= r.log.snapIndex {
+		return nil
+	}
+	return r.log.saveSnapshot(takeSnapshot(r.lastApplied, r.log.termAt(r.lastApplied)))
+}
+
+func (r *raftNode) RequestVote(ctx context.Context, req *rf.RequestVoteRequest) (*rf.RequestVoteResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.GetTerm() > r.currentTerm {
+		r.stepDown(req.GetTerm())
+	}
+	if req.GetTerm() < r.currentTerm {
+		return &rf.RequestVoteResponse{Term: r.currentTerm}, nil
+	}
+
+	upToDate := req.GetLastLogTerm() > r.log.lastTerm() ||
+		(req.GetLastLogTerm() == r.log.lastTerm() && req.GetLastLogIndex() >= r.log.lastIndex())
+	if (r.votedFor == "" || r.votedFor == req.GetCandidateId()) && upToDate {
+		r.votedFor = req.GetCandidateId()
+		r.lastContact = time.Now()
+		r.persistState()
+		return &rf.RequestVoteResponse{Term: r.currentTerm, VoteGranted: true}, nil
+	}
+	return &rf.RequestVoteResponse{Term: r.currentTerm}, nil
+}
+
+func (r *raftNode) AppendEntries(ctx context.Context, req *rf.AppendEntriesRequest) (*rf.AppendEntriesResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.GetTerm() < r.currentTerm {
+		return &rf.AppendEntriesResponse{Term: r.currentTerm}, nil
+	}
+	r.stepDown(req.GetTerm())
+	r.leaderId = req.GetLeaderId()
+	r.lastContact = time.Now()
+
+	prevIndex := req.GetPrevLogIndex()
+	if prevIndex > r.log.lastIndex() {
+		return &rf.AppendEntriesResponse{Term: r.currentTerm, ConflictIndex: r.log.lastIndex() + 1}, nil
+	}
+	if prevIndex > r.log.snapIndex && r.log.termAt(prevIndex) != req.GetPrevLogTerm() {
+		// Skip back over the whole conflicting term
+		conflictTerm := r.log.termAt(prevIndex)
+		conflict := prevIndex
+		for conflict > r.log.snapIndex+1 && r.log.termAt(conflict-1) == conflictTerm {
+			conflict--
+		}
+		return &rf.AppendEntriesResponse{Term: r.currentTerm, ConflictIndex: conflict}, nil
+	}
+
+	newEntries := make([]logEntry, 0, len(req.GetEntries()))
+	for _, e := range req.GetEntries() {
+		if e.GetIndex() <= r.log.snapIndex {
+			continue
+		}
+		if e.GetIndex() <= r.log.lastIndex() {
+			if r.log.termAt(e.GetIndex()) == e.GetTerm() {
+				continue
+			}
+			if err := r.log.truncate(e.GetIndex()); err != nil {
+				return nil, err
+			}
+		}
+		var entry logEntry
+		if err := json.Unmarshal(e.GetCommand(), &entry); err != nil {
+			return nil, err
+		}
+		newEntries = append(newEntries, entry)
+	}
+	if len(newEntries) > 0 {
+		if err := r.log.append(newEntries...); err != nil {
+			return nil, err
+		}
+	}
+
+	lastNew := prevIndex + uint64(len(req.GetEntries()))
+	// A stale request may cover fewer entries than are already known to be committed, so the commit
+	// index only ever moves forward
+	if commit := min(req.GetLeaderCommit(), lastNew); commit > r.commitIndex {
+		r.commitIndex = commit
+		r.cond.Broadcast()
+	}
+	return &rf.AppendEntriesResponse{Term: r.currentTerm, Success: true}, nil
+}
+
+func (r *raftNode) InstallSnapshot(ctx context.Context, req *rf.InstallSnapshotRequest) (*rf.InstallSnapshotResponse, error) {
+	// The tables are replaced, so take the apply lock before the state lock like the applier does
+	r.applyMu.Lock()
+	defer r.applyMu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if req.GetTerm() < r.currentTerm {
+		return &rf.InstallSnapshotResponse{Term: r.currentTerm}, nil
+	}
+	r.stepDown(req.GetTerm())
+	r.leaderId = req.GetLeaderId()
+	r.lastContact = time.Now()
+	if req.GetLastIncludedIndex() <= r.lastApplied {
+		return &rf.InstallSnapshotResponse{Term: r.currentTerm}, nil
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(req.GetData(), &snap); err != nil {
+		return nil, err
+	}
+	if err := r.log.saveSnapshot(snap); err != nil {
+		return nil, err
+	}
+	restoreSnapshot(snap)
+	r.lastApplied = snap.Index
+	if r.commitIndex < snap.Index {
+		r.commitIndex = snap.Index
+	}
+	fmt.Printf("[RAFT] Installed snapshot up to entry %d\n", snap.Index)
+	return &rf.InstallSnapshotResponse{Term: r.currentTerm}, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	rf "src/grpc/raft"
+)
+
+func raftEntries(t *testing.T, term uint64, indexes ...uint64) []*rf.LogEntry {
+	t.Helper()
+	entries := make([]*rf.LogEntry, 0, len(indexes))
+	for _, index := range indexes {
+		command, err := json.Marshal(logEntry{Index: index, Term: term, Op: opNoop})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, &rf.LogEntry{Index: index, Term: term, Command: command})
+	}
+	return entries
+}
+
+func TestAppendEntriesNeverMovesCommitBack(t *testing.T) {
+	l, _, err := openMetaLog(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+	r, err := newRaftNode("localhost:8080", nil, l, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, c := range []struct {
+		name string
+		req  *rf.AppendEntriesRequest
+		want uint64
+	}{
+		{"entries 1 to 3 committed", &rf.AppendEntriesRequest{Term: 1, Entries: raftEntries(t, 1, 1, 2, 3), LeaderCommit: 3}, 3},
+		{"stale request carrying entry 1 only", &rf.AppendEntriesRequest{Term: 1, Entries: raftEntries(t, 1, 1), LeaderCommit: 2}, 3},
+		{"reordered request carrying entry 1 only", &rf.AppendEntriesRequest{Term: 1, Entries: raftEntries(t, 1, 1), LeaderCommit: 5}, 3},
+		{"heartbeat with an older commit", &rf.AppendEntriesRequest{Term: 1, PrevLogIndex: 3, PrevLogTerm: 1, LeaderCommit: 1}, 3},
+		{"entry 4 committed", &rf.AppendEntriesRequest{Term: 1, PrevLogIndex: 3, PrevLogTerm: 1, Entries: raftEntries(t, 1, 4), LeaderCommit: 4}, 4},
+	} {
+		resp, err := r.AppendEntries(ctx, c.req)
+		if err != nil || !resp.GetSuccess() {
+			t.Fatalf("%s: rejected: %v", c.name, err)
+		}
+		if r.commitIndex != c.want {
+			t.Errorf("%s: commit index %d, want %d", c.name, r.commitIndex, c.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rebalancing is held while replicas are being moved, so only one rebalance runs at a time
+var rebalancing sync.Mutex
+
+// moving holds the blocks whose replica is being moved. Such a block has one copy too many between
+// the registration of the new copy and the removal of the old one, which the Replication loop must not trim.
+var moving = struct {
+	mu     sync.Mutex
+	blocks map[string]bool
+}{blocks: make(map[string]bool)}
+
+func setMoving(blockId string, on bool) {
+	moving.mu.Lock()
+	defer moving.mu.Unlock()
+	if on {
+		moving.blocks[blockId] = true
+	} else {
+		delete(moving.blocks, blockId)
+	}
+}
+
+func isMoving(blockId string) bool {
+	moving.mu.Lock()
+	defer moving.mu.Unlock()
+	return moving.blocks[blockId]
+}
+
+// rebalanceThreshold reads REBALANCE_THRESHOLD, how many percent a node may be above the average
+// utilisation before replicas are moved off it
+func rebalanceThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("REBALANCE_THRESHOLD"), 64)
+	if err != nil || threshold <= 0 || threshold > 100 {
+		return 10
+	}
+	return threshold
+}
+
+// rebalanceBandwidth reads REBALANCE_BANDWIDTH, the bytes per second the rebalancer may move
+func rebalanceBandwidth() int64 {
+	bandwidth, err := strconv.ParseInt(os.Getenv("REBALANCE_BANDWIDTH"), 10, 64)
+	if err != nil || bandwidth <= 0 {
+		return 10 << 20
+	}
+	return bandwidth
+}
+
+// rebalanceInterval reads REBALANCE_INTERVAL (in seconds). The rebalancer only runs on demand when it is unset.
+func rebalanceInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("REBALANCE_INTERVAL"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *masterServer) Rebalance(ctx context.Context, req *pb.RebalanceRequest) (*pb.RebalanceResponse, error) {
+	threshold := req.GetThreshold()
+	if threshold < 0 || threshold > 100 {
+		return nil, status.Error(codes.InvalidArgument, "the threshold must be between 0 and 100 percent")
+	}
+	if threshold == 0 {
+		threshold = rebalanceThreshold()
+	}
+	bandwidth := req.GetBandwidth()
+	if bandwidth <= 0 {
+		bandwidth = rebalanceBandwidth()
+	}
+	// A new leader plans from its tables only once it has applied the whole log
+	if !raft.caughtUp() {
+		return nil, status.Error(codes.Unavailable, "the master is still replaying the metadata log")
+	}
+	if !rebalancing.TryLock() {
+		return nil, status.Error(codes.FailedPrecondition, "a rebalance is already running")
+	}
+
+	average, moves := planRebalance(threshold)
+	logPlan(average, moves, req.GetDryRun())
+	if req.GetDryRun() {
+		rebalancing.Unlock()
+	} else {
+		go func() {
+			defer rebalancing.Unlock()
+			moveReplicas(moves, bandwidth)
+		}()
+	}
+	return &pb.RebalanceResponse{AverageUtilization: average, Moves: moves}, nil
+}
+
+// rebalanceLoop rebalances every REBALANCE_INTERVAL with the settings from the environment
+func rebalanceLoop() {
+	interval := rebalanceInterval()
+	if interval == 0 {
+		return
+	}
+	dryRun := os.Getenv("REBALANCE_DRY_RUN") == "true"
+	for {
+		time.Sleep(interval)
+		if !raft.caughtUp() || !rebalancing.TryLock() {
+			continue
+		}
+		average, moves := planRebalance(rebalanceThreshold())
+		if len(moves) > 0 {
+			logPlan(average, moves, dryRun)
+			if !dryRun {
+				moveReplicas(moves, rebalanceBandwidth())
+			}
+		}
+		rebalancing.Unlock()
+	}
+}
+
+// moveReplica copies a block from source to destination and then drops the copy on source. It reports
+// whether the block was moved.
+func moveReplica(blockId string, source int32, destination int32) bool {
+	setMoving(blockId, true)
+	defer setMoving(blockId, false)
+	success, err := replicateTo(blockId, source, destination)
+	if err != nil || !success {
+		fmt.Printf("[REBALANCE] Could not copy block %s to Data Keeper %d: %v\n", blockId, destination, err)
+		return false
+	}
+	if !holds(destination, blockId) {
+		fmt.Printf("[REBALANCE] Data Keeper %d did not register block %s\n", destination, blockId)
+		return false
+	}
+	if err := dropReplica(blockId, source); err != nil {
+		fmt.Printf("Error deleting block %s from Data Keeper %d: %v\n", blockId, source, err)
+	}
+	return true
+}
+
+// planRebalance picks replicas to move from nodes more than threshold percent above the average
+// utilisation to nodes below it. Utilisation comes from the heartbeats of alive nodes that are neither
+// suspect nor being decommissioned. It returns the average utilisation in percent and the moves.
+func planRebalance(threshold float64) (float64, []*pb.BlockMove) {
+	used := make(map[int32]int64)
+	capacity := make(map[int32]int64)
+	var totalUsed, totalCapacity int64
+	for _, id := range store.AliveNodeIds() {
+		node, _ := store.Node(id)
+		if node.state != stateAlive || node.decommissioning || node.capacity <= 0 {
+			continue
+		}
+		used[id] = node.used
+		capacity[id] = node.capacity
+		totalUsed += node.used
+		totalCapacity += node.capacity
+	}
+	if len(capacity) < 2 {
+		return 0, nil
+	}
+	average := float64(totalUsed) * 100 / float64(totalCapacity)
+	utilization := func(id int32) float64 {
+		return float64(used[id]) * 100 / float64(capacity[id])
+	}
+
+	sources := make([]int32, 0)
+	for id := range capacity {
+		if utilization(id) > average+threshold {
+			sources = append(sources, id)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool { return utilization(sources[i]) > utilization(sources[j]) })
+
+	// Only blocks of stored files are moved; uploads place their own blocks
+	targets := blockTargets()
+	moved := make(map[string]bool)
+	moves := make([]*pb.BlockMove, 0)
+	for _, source := range sources {
+		for _, replica := range store.ReplicasOnNode(source) {
+			if utilization(source) <= average+threshold {
+				break
+			}
+			if _, ok := targets[replica.BlockId]; !ok || moved[replica.BlockId] {
+				continue
+			}
+			holders := aliveHolders(replica.BlockId)
+			candidates := make([]int32, 0)
+			for id := range capacity {
+				if utilization(id) < average && used[id]+replica.Size <= capacity[id] && !contains(holders, id) {
+					candidates = append(candidates, id)
+				}
+			}
+			// The placement policy sees the holders the block keeps after the move
+			remaining := make([]int32, 0, len(holders))
+			for _, id := range holders {
+				if id != source {
+					remaining = append(remaining, id)
+				}
+			}
+			chosen := placement.Choose(placementNodes(candidates, nil), placementNodes(remaining, nil), 1)
+			if len(chosen) == 0 {
+				continue
+			}
+			destination := chosen[0]
+			used[source] -= replica.Size
+			used[destination] += replica.Size
+			moved[replica.BlockId] = true
+			moves = append(moves, &pb.BlockMove{BlockId: replica.BlockId, Size: replica.Size, Source: source, Destination: destination})
+		}
+	}
+	return average, moves
+}
+
+func contains(ids []int32, id int32) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func logPlan(average float64, moves []*pb.BlockMove, dryRun bool) {
+	fmt.Printf("[REBALANCE] Average utilisation %.1f%%, %d replicas to move\n", average, len(moves))
+	if !dryRun {
+		return
+	}
+	for _, move := range moves {
+		fmt.Printf("[REBALANCE] Would move block %s (%d bytes) from Data Keeper %d to Data Keeper %d\n",
+			move.GetBlockId(), move.GetSize(), move.GetSource(), move.GetDestination())
+	}
+}
+
+// moveReplicas copies every block to its destination through ReplicateFile and removes the source copy
+// once the destination registered it, moving at most bandwidth bytes per second
+func moveReplicas(moves []*pb.BlockMove, bandwidth int64) {
+	start := time.Now()
+	var bytes int64
+	done := 0
+	for _, move := range moves {
+		blockId := move.GetBlockId()
+		if !moveReplica(blockId, move.GetSource(), move.GetDestination()) {
+			continue
+		}
+		done++
+		bytes += move.GetSize()
+		fmt.Printf("[REBALANCE] Moved block %s from Data Keeper %d to Data Keeper %d\n", blockId, move.GetSource(), move.GetDestination())
+
+		// Sleep until the bytes moved so far fit the bandwidth
+		if wait := time.Duration(bytes*int64(time.Second)/bandwidth) - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	fmt.Printf("[REBALANCE] Moved %d of %d replicas (%d bytes)\n", done, len(moves), bytes)
+}
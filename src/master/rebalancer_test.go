@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlanRebalance(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		threshold float64
+		setup     func()
+		average   float64
+		want      map[int32]int // moves to each destination
+	}{
+		// Node 1 is at 90%, the average is 50%: three blocks bring it down to 60%
+		{"moves off the fullest node", 10, func() {}, 50, map[int32]int{2: 3}},
+		{"nothing above the threshold", 45, func() {}, 50, map[int32]int{}},
+		{"block already on the emptiest node", 10, func() {
+			store.AddReplica(BlockReplica{BlockId: "b0", DataNodeId: 2, Size: 10})
+		}, 50, map[int32]int{2: 3}},
+		// Without node 2 the average is 70%, and node 3 is the only one below it
+		{"decommissioning nodes are left out", 10, func() {
+			store.SetDecommissioning(2, true)
+		}, 70, map[int32]int{3: 1}},
+	} {
+		useNodes(t, leastUsedPolicy{}, 10, 90, 50)
+		for i := 0; i < 6; i++ {
+			blockId := fmt.Sprintf("b%d", i)
+			store.PutFile(testFile("/"+blockId, blockId))
+			store.AddReplica(BlockReplica{BlockId: blockId, DataNodeId: 1, Size: 10})
+		}
+		c.setup()
+
+		average, moves := planRebalance(c.threshold)
+		if average != c.average {
+			t.Errorf("%s: average utilisation %.1f, want %.1f", c.name, average, c.average)
+		}
+		got := make(map[int32]int)
+		seen := make(map[string]bool)
+		for _, move := range moves {
+			if move.GetSource() != 1 || seen[move.GetBlockId()] {
+				t.Errorf("%s: unexpected move %v", c.name, move)
+			}
+			if holds(move.GetDestination(), move.GetBlockId()) {
+				t.Errorf("%s: %s moved to a node that holds it", c.name, move.GetBlockId())
+			}
+			seen[move.GetBlockId()] = true
+			got[move.GetDestination()]++
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: moves per destination %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+// sessionTimeout bounds how long an upload may take between UploadFile and the last RegisterFile
+const sessionTimeout = time.Hour
+
+// uploadSession ties an upload to its file and to the client that has to be notified
+// once every block of the file is stored
+type uploadSession struct {
+	clientPort string
+	file       FileMetadata
+	placements []*pb.BlockPlacement
+	pending    map[string]bool // blocks not stored yet
+	started    time.Time
+	finished   bool // every block is stored; kept so blocks committed again are still recognised
+}
+
+// sessionTable tracks the uploads in progress on this master. Sessions are not replicated: after a
+// failover the new leader does not know them, and their clients have to start the upload again.
+type sessionTable struct {
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+}
+
+var uploadSessions = &sessionTable{sessions: make(map[string]*uploadSession)}
+
+var errSessionUnknown = errors.New("upload session is unknown or timed out")
+
+func newSessionId() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// start opens a session for a client uploading file to the given placements and returns its id
+func (t *sessionTable) start(clientPort string, file FileMetadata, placements []*pb.BlockPlacement) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, session := range t.sessions {
+		// Drop sessions whose upload never completed
+		if time.Since(session.started) > sessionTimeout {
+			delete(t.sessions, id)
+		} else if session.file.FileName == file.FileName && !session.finished {
+			return "", fmt.Errorf("file %s is already being uploaded", file.FileName)
+		}
+	}
+	id := newSessionId()
+	pending := make(map[string]bool, len(file.Blocks))
+	for _, blockId := range file.Blocks {
+		pending[blockId] = true
+	}
+	file.BlockChecksums = make([]string, len(file.Blocks))
+	t.sessions[id] = &uploadSession{clientPort: clientPort, file: file, placements: placements, pending: pending, started: time.Now()}
+	return id, nil
+}
+
+// resume returns the session uploading the same content to the path of file, so a client that was
+// interrupted can pick its upload up again, and notifications go to the client resuming it.
+// It fails if the path is being uploaded with other content.
+func (t *sessionTable) resume(clientPort string, file FileMetadata) (string, []*pb.BlockPlacement, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, session := range t.sessions {
+		if session.file.FileName != file.FileName || session.finished || time.Since(session.started) > sessionTimeout {
+			continue
+		}
+		if session.file.Size != file.Size || session.file.Checksum != file.Checksum ||
+			session.file.ContentType != file.ContentType || session.file.Replication != file.Replication {
+			return "", nil, false, fmt.Errorf("file %s is already being uploaded with other content", file.FileName)
+		}
+		session.clientPort = clientPort
+		return id, append([]*pb.BlockPlacement(nil), session.placements...), true, nil
+	}
+	return "", nil, false, nil
+}
+
+// uploading reports whether fileName is being uploaded
+func (t *sessionTable) uploading(fileName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, session := range t.sessions {
+		if session.file.FileName == fileName && !session.finished && time.Since(session.started) <= sessionTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingBlock returns where a block the session still waits for is uploaded
+func (t *sessionTable) pendingBlock(id string, blockId string) (*pb.BlockPlacement, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok || time.Since(session.started) > sessionTimeout {
+		return nil, errSessionUnknown
+	}
+	for _, placement := range session.placements {
+		if placement.GetBlockId() == blockId && session.pending[blockId] {
+			return placement, nil
+		}
+	}
+	return nil, fmt.Errorf("block %s is not waiting to be uploaded", blockId)
+}
+
+// move records that a block of the session is now uploaded to another data keeper
+func (t *sessionTable) move(id string, placement *pb.BlockPlacement) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return
+	}
+	for i, p := range session.placements {
+		if p.GetBlockId() == placement.GetBlockId() {
+			session.placements[i] = placement
+		}
+	}
+}
+
+// known reports whether id is a session of this master that has not timed out
+func (t *sessionTable) known(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	return ok && time.Since(session.started) <= sessionTimeout
+}
+
+// drop forgets a session whose file could not be created
+func (t *sessionTable) drop(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, id)
+}
+
+// blockStored marks a block of the session as stored with the given checksum. It reports whether the id
+// belongs to an upload still waiting for the block and, once the last block is in, finishes the session
+// and returns it as complete.
+func (t *sessionTable) blockStored(id string, blockId string, checksum string) (session uploadSession, complete bool, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.sessions[id]
+	if !ok || !s.pending[blockId] {
+		return uploadSession{}, false, false
+	}
+	delete(s.pending, blockId)
+	for i, id := range s.file.Blocks {
+		if id == blockId {
+			s.file.BlockChecksums[i] = checksum
+		}
+	}
+	if len(s.pending) > 0 {
+		return *s, false, true
+	}
+	s.finished = true
+	return *s, true, true
+}
+
+// blockIds returns the blocks of every upload in progress
+func (t *sessionTable) blockIds() map[string]bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	blockIds := make(map[string]bool)
+	for _, session := range t.sessions {
+		if session.finished || time.Since(session.started) > sessionTimeout {
+			continue
+		}
+		for _, blockId := range session.file.Blocks {
+			blockIds[blockId] = true
+		}
+	}
+	return blockIds
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pb "src/grpc/master"
+)
+
+func uploadFile(name string, size int64, checksum string) FileMetadata {
+	return FileMetadata{FileName: name, Size: size, Checksum: checksum, ContentType: "text/plain", Blocks: []string{name + "-b0", name + "-b1"}}
+}
+
+func TestSessionResume(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		prepare func(s *uploadSession)
+		again   FileMetadata
+		resumed bool
+		fails   bool
+	}{
+		{"same content", func(*uploadSession) {}, uploadFile("/a", 10, "x"), true, false},
+		{"other size", func(*uploadSession) {}, uploadFile("/a", 11, "x"), false, true},
+		{"other checksum", func(*uploadSession) {}, uploadFile("/a", 10, "y"), false, true},
+		{"other path", func(*uploadSession) {}, uploadFile("/b", 10, "x"), false, false},
+		{"finished upload", func(s *uploadSession) { s.finished = true }, uploadFile("/a", 10, "x"), false, false},
+		{"timed out upload", func(s *uploadSession) { s.started = time.Now().Add(-sessionTimeout - time.Second) }, uploadFile("/a", 10, "x"), false, false},
+	} {
+		table := &sessionTable{sessions: make(map[string]*uploadSession)}
+		placements := []*pb.BlockPlacement{{BlockId: "/a-b0", GrpcAddress: "localhost:9101"}, {BlockId: "/a-b1", GrpcAddress: "localhost:9102"}}
+		id, err := table.start("localhost:7000", uploadFile("/a", 10, "x"), placements)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.prepare(table.sessions[id])
+
+		resumedId, resumedPlacements, resumed, err := table.resume("localhost:7001", c.again)
+		if resumed != c.resumed || (err != nil) != c.fails {
+			t.Errorf("%s: resumed %v with error %v, want resumed %v and failure %v", c.name, resumed, err, c.resumed, c.fails)
+			continue
+		}
+		if !resumed {
+			continue
+		}
+		if resumedId != id || len(resumedPlacements) != 2 || resumedPlacements[1].GetGrpcAddress() != "localhost:9102" {
+			t.Errorf("%s: resumed %s at %v, want %s at the first placements", c.name, resumedId, resumedPlacements, id)
+		}
+		// The client that resumed is notified once the file is stored
+		if port := table.sessions[id].clientPort; port != "localhost:7001" {
+			t.Errorf("%s: notifications go to %s", c.name, port)
+		}
+	}
+}
+
+func TestSessionStart(t *testing.T) {
+	table := &sessionTable{sessions: make(map[string]*uploadSession)}
+	id, err := table.start("", uploadFile("/a", 10, "x"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.start("", uploadFile("/a", 10, "y"), nil); err == nil {
+		t.Error("two uploads to the same path at once")
+	}
+	if !table.uploading("/a") || !table.known(id) {
+		t.Error("session not listed")
+	}
+
+	for i, blockId := range []string{"/a-b0", "/a-b1"} {
+		session, complete, ok := table.blockStored(id, blockId, "sum")
+		if !ok || complete != (i == 1) {
+			t.Fatalf("block %s: stored %v, complete %v", blockId, ok, complete)
+		}
+		if complete && session.file.BlockChecksums[1] != "sum" {
+			t.Error("block checksum not recorded")
+		}
+	}
+	if _, _, ok := table.blockStored(id, "/a-b1", "sum"); ok {
+		t.Error("block stored twice")
+	}
+	// A finished upload no longer locks its path
+	if table.uploading("/a") || len(table.blockIds()) != 0 {
+		t.Error("finished upload still in progress")
+	}
+	if _, err := table.start("", uploadFile("/a", 10, "y"), nil); err != nil {
+		t.Errorf("path locked by a finished upload: %v", err)
+	}
+}
+
+func TestSessionMoveBlock(t *testing.T) {
+	table := &sessionTable{sessions: make(map[string]*uploadSession)}
+	placements := []*pb.BlockPlacement{{BlockId: "/a-b0", Size: 5, GrpcAddress: "localhost:9101"}, {BlockId: "/a-b1", Size: 5, GrpcAddress: "localhost:9101"}}
+	id, err := table.start("", uploadFile("/a", 10, "x"), placements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.blockStored(id, "/a-b0", "sum")
+
+	for _, c := range []struct {
+		name    string
+		id      string
+		blockId string
+		ok      bool
+		unknown bool
+	}{
+		{"pending block", id, "/a-b1", true, false},
+		{"stored block", id, "/a-b0", false, false},
+		{"block of another file", id, "/b-b0", false, false},
+		{"unknown session", "nope", "/a-b1", false, true},
+	} {
+		placement, err := table.pendingBlock(c.id, c.blockId)
+		if (err == nil) != c.ok || errors.Is(err, errSessionUnknown) != c.unknown {
+			t.Errorf("%s: %v, %v", c.name, placement, err)
+		}
+	}
+
+	table.move(id, &pb.BlockPlacement{BlockId: "/a-b1", Size: 5, GrpcAddress: "localhost:9102"})
+	if placement, _ := table.pendingBlock(id, "/a-b1"); placement.GetGrpcAddress() != "localhost:9102" {
+		t.Errorf("block still placed at %s", placement.GetGrpcAddress())
+	}
+	// A resumed upload is sent to where the block was moved
+	_, resumed, _, _ := table.resume("", uploadFile("/a", 10, "x"))
+	if resumed[0].GetGrpcAddress() != "localhost:9101" || resumed[1].GetGrpcAddress() != "localhost:9102" {
+		t.Errorf("resumed placements %v", resumed)
+	}
+}
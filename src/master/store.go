@@ -0,0 +1,555 @@
+package main
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetadataStore holds the directory, file, block and data node tables of the master.
+// Implementations must be safe for concurrent use by the gRPC handlers and the background loops.
+type MetadataStore interface {
+	// PutFile adds a file and its block list. It fails if the path is taken or its directory is missing.
+	PutFile(file FileMetadata) error
+	// File returns the file with the given name
+	File(fileName string) (FileMetadata, bool)
+	// HasFile reports whether fileName is known
+	HasFile(fileName string) bool
+	// FileOfBlock returns the name of the file blockId belongs to
+	FileOfBlock(blockId string) (string, bool)
+	// FileList returns every file sorted by name
+	FileList() []FileMetadata
+	// DeleteFile forgets a file together with every replica of its blocks
+	DeleteFile(fileName string)
+	// RenameFile moves the file oldName to file.FileName, keeping its blocks. It fails if oldName
+	// is gone, or if the new path is taken or its directory is missing.
+	RenameFile(oldName string, file FileMetadata) error
+
+	// PutDir adds a directory. It fails if the path is taken or its parent is missing.
+	PutDir(dir Directory) error
+	// RemoveDir forgets a directory. It fails if the directory is missing or not empty.
+	RemoveDir(path string) error
+	// HasDir reports whether path is a directory. The root always exists.
+	HasDir(path string) bool
+	// Dir returns the directory at path
+	Dir(path string) (Directory, bool)
+	// SetReplication changes the replication factor of the file or directory at path
+	SetReplication(path string, replication int32)
+	// List returns the directories and files inside dirPath sorted by path,
+	// including every descendant when recursive is set
+	List(dirPath string, recursive bool) ([]Directory, []FileMetadata)
+
+	// AddReplica records that a copy of the block is stored on replica.DataNodeId
+	AddReplica(replica BlockReplica)
+	// RemoveReplica forgets the copy of blockId stored on dataNodeId
+	RemoveReplica(blockId string, dataNodeId int32)
+	// Replicas returns every known replica of blockId
+	Replicas(blockId string) []BlockReplica
+	// ReplicasOnNode returns every replica stored on dataNodeId
+	ReplicasOnNode(dataNodeId int32) []BlockReplica
+	// AllReplicas returns every replica of every block
+	AllReplicas() []BlockReplica
+
+	// PutNode adds a data node or updates its addresses and labels, keeping its liveness
+	PutNode(record nodeRecord)
+	// Node returns the data node with the given id
+	Node(id int32) (dataNode, bool)
+	// NodeIds returns the ids of every known data node in join order
+	NodeIds() []int32
+	// AliveNodeIds returns the ids of the data nodes currently considered alive
+	AliveNodeIds() []int32
+	// SetState changes the liveness of a data node as seen by the failure detector
+	SetState(id int32, state nodeState)
+	// SetDecommissioning marks a data node as being retired, so no new blocks are placed on it
+	SetDecommissioning(id int32, decommissioning bool)
+
+	// Heartbeat records the load a data node reported with its latest heartbeat
+	Heartbeat(id int32, stats nodeStats)
+	// BlockReport replaces the list of blocks a data node says it stores
+	BlockReport(id int32, blocks []string)
+	// MissingReplicas returns the replicas on alive data nodes that the node's latest block report left out.
+	// Replicas added less than slack before the report are not counted, as the report may predate them.
+	MissingReplicas(slack time.Duration) []BlockReplica
+
+	// Snapshot copies the persistent part of the tables
+	Snapshot() snapshot
+	// Restore replaces the directory, file and block tables and merges the data nodes from a snapshot
+	Restore(snap snapshot)
+}
+
+// Errors of file table changes whose preconditions no longer hold when they are applied
+var (
+	errFileExists   = errors.New("path already exists")
+	errFileNotFound = errors.New("file not found")
+	errDirNotFound  = errors.New("directory not found")
+	errDirNotEmpty  = errors.New("directory not empty")
+)
+
+// memoryStore is a MetadataStore kept in memory and indexed by path, block id and data node id
+type memoryStore struct {
+	mu       sync.RWMutex
+	dirs     map[string]Directory
+	files    map[string]FileMetadata
+	blocks   map[string]string // file of every block
+	replicas map[string][]BlockReplica
+	byNode   map[int32]map[string]BlockReplica
+	nodes    map[int32]dataNode
+	ids      []int32
+	addedAt  map[int32]map[string]time.Time // when each replica was registered
+	reports  map[int32]blockReport
+}
+
+// nodeStats is the load a data node reports with its heartbeats
+type nodeStats struct {
+	capacity  int64 // bytes the node may use for blocks, 0 until reported
+	used      int64
+	available int64
+	inFlight  int32
+}
+
+// blockReport is the latest full list of blocks received from a data node
+type blockReport struct {
+	blocks   map[string]bool
+	received time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		dirs:     make(map[string]Directory),
+		files:    make(map[string]FileMetadata),
+		blocks:   make(map[string]string),
+		replicas: make(map[string][]BlockReplica),
+		byNode:   make(map[int32]map[string]BlockReplica),
+		nodes:    make(map[int32]dataNode),
+		ids:      make([]int32, 0),
+		addedAt:  make(map[int32]map[string]time.Time),
+		reports:  make(map[int32]blockReport),
+	}
+}
+
+func (m *memoryStore) PutFile(file FileMetadata) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkNewPath(file.FileName); err != nil {
+		return err
+	}
+	m.putFile(file)
+	return nil
+}
+
+// putFile must be called with m.mu held
+func (m *memoryStore) putFile(file FileMetadata) {
+	m.files[file.FileName] = file
+	for _, blockId := range file.Blocks {
+		m.blocks[blockId] = file.FileName
+	}
+}
+
+// checkNewPath must be called with m.mu held
+func (m *memoryStore) checkNewPath(p string) error {
+	if _, ok := m.files[p]; ok {
+		return errFileExists
+	}
+	if _, ok := m.dirs[p]; ok || p == "/" {
+		return errFileExists
+	}
+	if parent := path.Dir(p); parent != "/" {
+		if _, ok := m.dirs[parent]; !ok {
+			return errDirNotFound
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) File(fileName string) (FileMetadata, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	file, ok := m.files[fileName]
+	return file, ok
+}
+
+func (m *memoryStore) FileOfBlock(blockId string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	fileName, ok := m.blocks[blockId]
+	return fileName, ok
+}
+
+func (m *memoryStore) HasFile(fileName string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.files[fileName]
+	return ok
+}
+
+func (m *memoryStore) FileList() []FileMetadata {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	files := make([]FileMetadata, 0, len(m.files))
+	for _, file := range m.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].FileName < files[j].FileName })
+	return files
+}
+
+func (m *memoryStore) DeleteFile(fileName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteFile(fileName)
+}
+
+// deleteFile must be called with m.mu held
+func (m *memoryStore) deleteFile(fileName string) {
+	file, ok := m.files[fileName]
+	if !ok {
+		return
+	}
+	for _, blockId := range file.Blocks {
+		for _, replica := range m.replicas[blockId] {
+			delete(m.byNode[replica.DataNodeId], blockId)
+			delete(m.addedAt[replica.DataNodeId], blockId)
+		}
+		delete(m.replicas, blockId)
+		delete(m.blocks, blockId)
+	}
+	delete(m.files, fileName)
+}
+
+func (m *memoryStore) RenameFile(oldName string, file FileMetadata) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[oldName]; !ok {
+		return errFileNotFound
+	}
+	if err := m.checkNewPath(file.FileName); err != nil {
+		return err
+	}
+	delete(m.files, oldName)
+	m.putFile(file)
+	return nil
+}
+
+func (m *memoryStore) PutDir(dir Directory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkNewPath(dir.Path); err != nil {
+		return err
+	}
+	m.dirs[dir.Path] = dir
+	return nil
+}
+
+func (m *memoryStore) RemoveDir(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.dirs[path]; !ok {
+		return errDirNotFound
+	}
+	for dirPath := range m.dirs {
+		if inDir(dirPath, path, false) {
+			return errDirNotEmpty
+		}
+	}
+	for fileName := range m.files {
+		if inDir(fileName, path, false) {
+			return errDirNotEmpty
+		}
+	}
+	delete(m.dirs, path)
+	return nil
+}
+
+func (m *memoryStore) Dir(path string) (Directory, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	dir, ok := m.dirs[path]
+	if !ok && path == "/" {
+		return Directory{Path: path}, true
+	}
+	return dir, ok
+}
+
+func (m *memoryStore) SetReplication(path string, replication int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if file, ok := m.files[path]; ok {
+		file.Replication = replication
+		m.files[path] = file
+		return
+	}
+	// The root is only stored once it has settings of its own
+	if dir, ok := m.dirs[path]; ok || path == "/" {
+		dir.Path = path
+		dir.Replication = replication
+		m.dirs[path] = dir
+	}
+}
+
+func (m *memoryStore) HasDir(path string) bool {
+	if path == "/" {
+		return true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.dirs[path]
+	return ok
+}
+
+// inDir reports whether path lies inside dirPath, directly or anywhere below it when recursive is set
+func inDir(path string, dirPath string, recursive bool) bool {
+	prefix := strings.TrimSuffix(dirPath, "/") + "/"
+	if path == dirPath || !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return recursive || !strings.Contains(path[len(prefix):], "/")
+}
+
+func (m *memoryStore) List(dirPath string, recursive bool) ([]Directory, []FileMetadata) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	dirs := make([]Directory, 0)
+	for path, dir := range m.dirs {
+		if inDir(path, dirPath, recursive) {
+			dirs = append(dirs, dir)
+		}
+	}
+	files := make([]FileMetadata, 0)
+	for path, file := range m.files {
+		if inDir(path, dirPath, recursive) {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	sort.Slice(files, func(i, j int) bool { return files[i].FileName < files[j].FileName })
+	return dirs, files
+}
+
+func (m *memoryStore) AddReplica(replica BlockReplica) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addReplica(replica)
+}
+
+// addReplica must be called with m.mu held
+func (m *memoryStore) addReplica(replica BlockReplica) {
+	if _, exists := m.byNode[replica.DataNodeId][replica.BlockId]; exists {
+		m.removeReplica(replica.BlockId, replica.DataNodeId)
+	}
+	m.replicas[replica.BlockId] = append(m.replicas[replica.BlockId], replica)
+	if m.byNode[replica.DataNodeId] == nil {
+		m.byNode[replica.DataNodeId] = make(map[string]BlockReplica)
+	}
+	m.byNode[replica.DataNodeId][replica.BlockId] = replica
+	if m.addedAt[replica.DataNodeId] == nil {
+		m.addedAt[replica.DataNodeId] = make(map[string]time.Time)
+	}
+	m.addedAt[replica.DataNodeId][replica.BlockId] = time.Now()
+}
+
+func (m *memoryStore) RemoveReplica(blockId string, dataNodeId int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeReplica(blockId, dataNodeId)
+}
+
+// removeReplica must be called with m.mu held
+func (m *memoryStore) removeReplica(blockId string, dataNodeId int32) {
+	replicas := m.replicas[blockId]
+	kept := make([]BlockReplica, 0, len(replicas))
+	for _, replica := range replicas {
+		if replica.DataNodeId != dataNodeId {
+			kept = append(kept, replica)
+		}
+	}
+	if len(kept) == 0 {
+		delete(m.replicas, blockId)
+	} else {
+		m.replicas[blockId] = kept
+	}
+	delete(m.byNode[dataNodeId], blockId)
+	delete(m.addedAt[dataNodeId], blockId)
+}
+
+func (m *memoryStore) Replicas(blockId string) []BlockReplica {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]BlockReplica(nil), m.replicas[blockId]...)
+}
+
+func (m *memoryStore) ReplicasOnNode(dataNodeId int32) []BlockReplica {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	replicas := make([]BlockReplica, 0, len(m.byNode[dataNodeId]))
+	for _, replica := range m.byNode[dataNodeId] {
+		replicas = append(replicas, replica)
+	}
+	sort.Slice(replicas, func(i, j int) bool { return replicas[i].BlockId < replicas[j].BlockId })
+	return replicas
+}
+
+func (m *memoryStore) AllReplicas() []BlockReplica {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blockIds := make([]string, 0, len(m.replicas))
+	for blockId := range m.replicas {
+		blockIds = append(blockIds, blockId)
+	}
+	sort.Strings(blockIds)
+	replicas := make([]BlockReplica, 0, len(blockIds))
+	for _, blockId := range blockIds {
+		replicas = append(replicas, m.replicas[blockId]...)
+	}
+	return replicas
+}
+
+func (m *memoryStore) PutNode(record nodeRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putNode(record)
+}
+
+// putNode must be called with m.mu held
+func (m *memoryStore) putNode(record nodeRecord) {
+	node, exists := m.nodes[record.Id]
+	node.downloadAddress = record.DownloadAddress
+	node.rack = record.Rack
+	node.zone = record.Zone
+	m.nodes[record.Id] = node
+	if !exists {
+		node.state = stateDead
+		m.nodes[record.Id] = node
+		m.ids = append(m.ids, record.Id)
+	}
+}
+
+func (m *memoryStore) Node(id int32) (dataNode, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	node, ok := m.nodes[id]
+	return node, ok
+}
+
+func (m *memoryStore) NodeIds() []int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]int32(nil), m.ids...)
+}
+
+func (m *memoryStore) AliveNodeIds() []int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	alive := make([]int32, 0, len(m.ids))
+	for _, id := range m.ids {
+		if m.nodes[id].isAlive {
+			alive = append(alive, id)
+		}
+	}
+	return alive
+}
+
+func (m *memoryStore) SetState(id int32, state nodeState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, ok := m.nodes[id]
+	if !ok {
+		return
+	}
+	node.state = state
+	node.isAlive = state != stateDead
+	m.nodes[id] = node
+}
+
+func (m *memoryStore) SetDecommissioning(id int32, decommissioning bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, ok := m.nodes[id]
+	if !ok {
+		return
+	}
+	node.decommissioning = decommissioning
+	m.nodes[id] = node
+}
+
+func (m *memoryStore) Heartbeat(id int32, stats nodeStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if node, ok := m.nodes[id]; ok {
+		node.nodeStats = stats
+		m.nodes[id] = node
+	}
+}
+
+func (m *memoryStore) BlockReport(id int32, blocks []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report := blockReport{blocks: make(map[string]bool, len(blocks)), received: time.Now()}
+	for _, blockId := range blocks {
+		report.blocks[blockId] = true
+	}
+	m.reports[id] = report
+}
+
+func (m *memoryStore) MissingReplicas(slack time.Duration) []BlockReplica {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	missing := make([]BlockReplica, 0)
+	for id, report := range m.reports {
+		if !m.nodes[id].isAlive {
+			continue
+		}
+		for blockId, replica := range m.byNode[id] {
+			if !report.blocks[blockId] && m.addedAt[id][blockId].Add(slack).Before(report.received) {
+				missing = append(missing, replica)
+			}
+		}
+	}
+	return missing
+}
+
+func (m *memoryStore) Snapshot() snapshot {
+	files := m.FileList()
+	replicas := m.AllReplicas()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	dirs := make([]Directory, 0, len(m.dirs))
+	for _, dir := range m.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+	nodes := make([]nodeRecord, 0, len(m.ids))
+	for _, id := range m.ids {
+		node := m.nodes[id]
+		nodes = append(nodes, nodeRecord{Id: id, DownloadAddress: node.downloadAddress, Rack: node.rack, Zone: node.zone, Decommissioning: node.decommissioning})
+	}
+	return snapshot{Dirs: dirs, Files: files, Replicas: replicas, Nodes: nodes}
+}
+
+func (m *memoryStore) Restore(snap snapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirs = make(map[string]Directory)
+	m.files = make(map[string]FileMetadata)
+	m.blocks = make(map[string]string)
+	m.replicas = make(map[string][]BlockReplica)
+	m.byNode = make(map[int32]map[string]BlockReplica)
+	m.addedAt = make(map[int32]map[string]time.Time)
+	for _, record := range snap.Nodes {
+		m.putNode(record)
+		node := m.nodes[record.Id]
+		node.decommissioning = record.Decommissioning
+		m.nodes[record.Id] = node
+	}
+	for _, dir := range snap.Dirs {
+		m.dirs[dir.Path] = dir
+	}
+	for _, file := range snap.Files {
+		m.putFile(file)
+	}
+	for _, replica := range snap.Replicas {
+		m.addReplica(replica)
+	}
+}
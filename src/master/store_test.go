@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// checkIndex fails the test unless the per-block and per-node replica tables list the same replicas
+func checkIndex(t *testing.T, m *memoryStore) {
+	t.Helper()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for blockId, replicas := range m.replicas {
+		if len(replicas) == 0 {
+			t.Errorf("block %s kept with no replicas", blockId)
+		}
+		for _, replica := range replicas {
+			if _, ok := m.byNode[replica.DataNodeId][blockId]; !ok {
+				t.Errorf("replica of %s on node %d missing from the node index", blockId, replica.DataNodeId)
+			}
+			count++
+		}
+	}
+	indexed := 0
+	for node, blocks := range m.byNode {
+		for blockId := range blocks {
+			if _, ok := m.addedAt[node][blockId]; !ok {
+				t.Errorf("replica of %s on node %d has no registration time", blockId, node)
+			}
+			indexed++
+		}
+	}
+	if count != indexed {
+		t.Errorf("%d replicas by block, %d by node", count, indexed)
+	}
+}
+
+func testFile(name string, blocks ...string) FileMetadata {
+	return FileMetadata{FileName: name, Blocks: blocks, BlockChecksums: make([]string, len(blocks))}
+}
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	m := newMemoryStore()
+	for id := int32(1); id <= 3; id++ {
+		m.PutNode(nodeRecord{Id: id, DownloadAddress: fmt.Sprintf("localhost:910%d", id)})
+	}
+
+	var wg sync.WaitGroup
+	// Readers run alongside the writers
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if snap := m.Snapshot(); len(snap.Replicas) > 0 {
+					m.Replicas(snap.Replicas[len(snap.Replicas)-1].BlockId)
+				}
+				m.ReplicasOnNode(1)
+				m.FileList()
+			}
+		}()
+	}
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				name := fmt.Sprintf("/f%d-%d", g, i)
+				blocks := []string{name + "-b0", name + "-b1"}
+				if err := m.PutFile(testFile(name, blocks...)); err != nil {
+					t.Error(err)
+					return
+				}
+				for _, blockId := range blocks {
+					for id := int32(1); id <= 3; id++ {
+						m.AddReplica(BlockReplica{BlockId: blockId, DataNodeId: id, Size: 1})
+					}
+				}
+				if n := len(m.Replicas(blocks[0])); n != 3 {
+					t.Errorf("%s has %d replicas, want 3", blocks[0], n)
+				}
+				// Every other file goes away again
+				if i%2 == 0 {
+					m.DeleteFile(name)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	checkIndex(t, m)
+	if n := len(m.FileList()); n != 8*10 {
+		t.Errorf("%d files left, want %d", n, 8*10)
+	}
+	if n := len(m.ReplicasOnNode(2)); n != 8*10*2 {
+		t.Errorf("%d replicas on node 2, want %d", n, 8*10*2)
+	}
+}
+
+func TestMemoryStoreDeleteFileDropsReplicas(t *testing.T) {
+	m := newMemoryStore()
+	m.PutFile(testFile("/a", "b1", "b2"))
+	m.PutFile(testFile("/b", "b3"))
+	for _, blockId := range []string{"b1", "b2", "b3"} {
+		m.AddReplica(BlockReplica{BlockId: blockId, DataNodeId: 1})
+		m.AddReplica(BlockReplica{BlockId: blockId, DataNodeId: 2})
+	}
+	// Registering a replica again replaces it
+	m.AddReplica(BlockReplica{BlockId: "b1", DataNodeId: 1, Size: 7})
+	if replicas := m.Replicas("b1"); len(replicas) != 2 {
+		t.Fatalf("b1 has %d replicas, want 2", len(replicas))
+	}
+
+	m.DeleteFile("/a")
+	checkIndex(t, m)
+	if len(m.Replicas("b1")) != 0 || len(m.Replicas("b2")) != 0 {
+		t.Error("replicas of a deleted file kept")
+	}
+	if _, ok := m.FileOfBlock("b1"); ok {
+		t.Error("block of a deleted file still belongs to it")
+	}
+	for _, id := range []int32{1, 2} {
+		if replicas := m.ReplicasOnNode(id); len(replicas) != 1 || replicas[0].BlockId != "b3" {
+			t.Errorf("node %d holds %v, want only b3", id, replicas)
+		}
+	}
+
+	m.RemoveReplica("b3", 1)
+	checkIndex(t, m)
+	if len(m.ReplicasOnNode(1)) != 0 || len(m.Replicas("b3")) != 1 {
+		t.Error("removed replica still indexed")
+	}
+}
+
+func TestMemoryStoreRenameFile(t *testing.T) {
+	m := newMemoryStore()
+	m.PutDir(Directory{Path: "/d"})
+	m.PutFile(testFile("/a", "b1"))
+	m.PutFile(testFile("/b", "b2"))
+	m.AddReplica(BlockReplica{BlockId: "b1", DataNodeId: 1})
+	m.AddReplica(BlockReplica{BlockId: "b2", DataNodeId: 1})
+
+	if err := m.RenameFile("/a", testFile("/d/a", "b1")); err != nil {
+		t.Fatal(err)
+	}
+	checkIndex(t, m)
+	if m.HasFile("/a") || !m.HasFile("/d/a") {
+		t.Fatal("file not moved")
+	}
+	if len(m.Replicas("b1")) != 1 || len(m.ReplicasOnNode(1)) != 2 {
+		t.Error("replicas changed by a rename")
+	}
+	if fileName, _ := m.FileOfBlock("b1"); fileName != "/d/a" {
+		t.Errorf("block b1 belongs to %q after the rename, want /d/a", fileName)
+	}
+
+	// A rename that was valid when proposed may no longer be when it is applied
+	for _, c := range []struct {
+		from, to string
+		want     error
+	}{
+		{"/b", "/d/a", errFileExists},
+		{"/b", "/d", errFileExists},
+		{"/a", "/c", errFileNotFound},
+		{"/b", "/e/b", errDirNotFound},
+	} {
+		if err := m.RenameFile(c.from, testFile(c.to, "b2")); !errors.Is(err, c.want) {
+			t.Errorf("rename %s to %s: %v, want %v", c.from, c.to, err, c.want)
+		}
+	}
+	if file, _ := m.File("/d/a"); len(file.Blocks) != 1 || file.Blocks[0] != "b1" {
+		t.Error("existing file replaced")
+	}
+	if err := m.PutFile(testFile("/b", "b4")); !errors.Is(err, errFileExists) {
+		t.Errorf("PutFile over an existing file: %v", err)
+	}
+	checkIndex(t, m)
+}
+
+func TestMemoryStoreDirectories(t *testing.T) {
+	m := newMemoryStore()
+	if err := m.PutDir(Directory{Path: "/d", Replication: 2}); err != nil {
+		t.Fatal(err)
+	}
+	m.PutDir(Directory{Path: "/d/e"})
+	m.PutFile(testFile("/d/f", "b1"))
+
+	// Directory changes proposed before a conflicting one was applied must fail when they are applied
+	for _, c := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"mkdir over a directory", m.PutDir(Directory{Path: "/d"}), errFileExists},
+		{"mkdir over a file", m.PutDir(Directory{Path: "/d/f"}), errFileExists},
+		{"mkdir without parent", m.PutDir(Directory{Path: "/x/y"}), errDirNotFound},
+		{"rmdir of a non-empty directory", m.RemoveDir("/d"), errDirNotEmpty},
+		{"rmdir of a missing directory", m.RemoveDir("/x"), errDirNotFound},
+	} {
+		if !errors.Is(c.err, c.want) {
+			t.Errorf("%s: %v, want %v", c.name, c.err, c.want)
+		}
+	}
+	if dir, _ := m.Dir("/d"); dir.Replication != 2 {
+		t.Error("existing directory replaced")
+	}
+
+	if err := m.RemoveDir("/d/e"); err != nil {
+		t.Fatal(err)
+	}
+	m.DeleteFile("/d/f")
+	if err := m.RemoveDir("/d"); err != nil || m.HasDir("/d") {
+		t.Errorf("empty directory not removed: %v", err)
+	}
+}
+
+func TestMemoryStoreSnapshotRestore(t *testing.T) {
+	m := newMemoryStore()
+	m.PutNode(nodeRecord{Id: 1, DownloadAddress: "localhost:9101"})
+	m.PutDir(Directory{Path: "/d", Replication: 2})
+	m.PutFile(testFile("/d/a", "b1"))
+	m.AddReplica(BlockReplica{BlockId: "b1", DataNodeId: 1, Size: 3})
+
+	restored := newMemoryStore()
+	restored.Restore(m.Snapshot())
+	checkIndex(t, restored)
+	if !restored.HasFile("/d/a") || !restored.HasDir("/d") || len(restored.ReplicasOnNode(1)) != 1 {
+		t.Fatal("tables not restored")
+	}
+	if fileName, _ := restored.FileOfBlock("b1"); fileName != "/d/a" {
+		t.Errorf("block b1 belongs to %q after restoring, want /d/a", fileName)
+	}
+	if node, ok := restored.Node(1); !ok || node.isAlive {
+		t.Error("a restored data node must stay dead until it sends a heartbeat")
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Operations recorded in the metadata log
+const (
+	opNoop           = "noop"
+	opCreateFile     = "create_file"
+	opRegisterBlock  = "register_block"
+	opJoin           = "join"
+	opRemoveBlock    = "remove_block"
+	opDeleteFile     = "delete_file"
+	opRenameFile     = "rename_file"
+	opMkdir          = "mkdir"
+	opRmdir          = "rmdir"
+	opSetReplication = "set_replication"
+	opDecommission   = "decommission"
+)
+
+// nodeRecord is the persisted form of a data keeper in the lookup table
+type nodeRecord struct {
+	Id              int32  `json:"id"`
+	DownloadAddress string `json:"downloadAddress"`
+	Rack            string `json:"rack,omitempty"`
+	Zone            string `json:"zone,omitempty"`
+	Decommissioning bool   `json:"decommissioning,omitempty"`
+}
+
+// logEntry is a single mutation of the master metadata. Index and Term place it in the replicated log.
+type logEntry struct {
+	Index       uint64        `json:"index"`
+	Term        uint64        `json:"term"`
+	Op          string        `json:"op"`
+	File        *FileMetadata `json:"file,omitempty"`
+	Replica     *BlockReplica `json:"replica,omitempty"`
+	Node        *nodeRecord   `json:"node,omitempty"`
+	Dir         *Directory    `json:"dir,omitempty"`
+	OldName     string        `json:"oldName,omitempty"`
+	Path        string        `json:"path,omitempty"`
+	Replication int32         `json:"replication,omitempty"`
+}
+
+// snapshot is a compacted copy of the whole master metadata up to Index
+type snapshot struct {
+	Index    uint64         `json:"index"`
+	Term     uint64         `json:"term"`
+	Dirs     []Directory    `json:"dirs"`
+	Files    []FileMetadata `json:"files"`
+	Replicas []BlockReplica `json:"replicas"`
+	Nodes    []nodeRecord   `json:"nodes"`
+}
+
+// hardState is the part of the consensus state that must survive restarts
+type hardState struct {
+	Term     uint64 `json:"term"`
+	VotedFor string `json:"votedFor"`
+}
+
+// metaLog is the write-ahead log and snapshot pair kept on the master's disk.
+// It is not safe for concurrent use; the raft node serializes access to it.
+type metaLog struct {
+	dir       string
+	file      *os.File
+	entries   []logEntry // entries after the snapshot
+	snapIndex uint64
+	snapTerm  uint64
+}
+
+func (l *metaLog) logPath() string {
+	return filepath.Join(l.dir, "metadata.log")
+}
+
+func (l *metaLog) snapshotPath() string {
+	return filepath.Join(l.dir, "metadata.snapshot")
+}
+
+func (l *metaLog) statePath() string {
+	return filepath.Join(l.dir, "raft.state")
+}
+
+// openMetaLog creates the data directory if needed, loads the snapshot header and
+// the log entries after it, and opens the log for appending
+func openMetaLog(dir string) (*metaLog, *snapshot, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+	l := &metaLog{dir: dir}
+
+	snap, err := l.loadSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	if snap != nil {
+		l.snapIndex = snap.Index
+		l.snapTerm = snap.Term
+	}
+	if err := l.loadEntries(); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.OpenFile(l.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.file = file
+	return l, snap, nil
+}
+
+func (l *metaLog) loadSnapshot() (*snapshot, error) {
+	data, err := os.ReadFile(l.snapshotPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("corrupt snapshot: %v", err)
+	}
+	return &snap, nil
+}
+
+func (l *metaLog) loadEntries() error {
+	file, err := os.Open(l.logPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var offset int64
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := scanner.Bytes()
+		var entry logEntry
+		if err := json.Unmarshal(record, &entry); err != nil {
+			// A torn write can only be the last record. Anything after it means the log is damaged,
+			// and skipping the record would shift every later entry to the wrong index.
+			for scanner.Scan() {
+				if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+					return fmt.Errorf("corrupt metadata log: unreadable entry on line %d is followed by more entries: %v", line, err)
+				}
+			}
+			if scanErr := scanner.Err(); scanErr != nil {
+				return scanErr
+			}
+			fmt.Println("Dropping torn entry at the end of the metadata log:", err)
+			return os.Truncate(l.logPath(), offset)
+		}
+		offset += int64(len(record)) + 1
+		if entry.Index <= l.snapIndex {
+			continue
+		}
+		if entry.Index > l.lastIndex()+1 {
+			return fmt.Errorf("corrupt metadata log: entry %d on line %d follows entry %d", entry.Index, line, l.lastIndex())
+		}
+		// A later entry with the same index replaces a truncated one
+		if entry.Index <= l.lastIndex() {
+			l.entries = l.entries[:entry.Index-l.snapIndex-1]
+		}
+		l.entries = append(l.entries, entry)
+	}
+	return scanner.Err()
+}
+
+func (l *metaLog) lastIndex() uint64 {
+	return l.snapIndex + uint64(len(l.entries))
+}
+
+func (l *metaLog) lastTerm() uint64 {
+	if len(l.entries) == 0 {
+		return l.snapTerm
+	}
+	return l.entries[len(l.entries)-1].Term
+}
+
+// termAt returns the term of the entry at index, or 0 if it was compacted away or does not exist
+func (l *metaLog) termAt(index uint64) uint64 {
+	if index == l.snapIndex {
+		return l.snapTerm
+	}
+	if index < l.snapIndex || index > l.lastIndex() {
+		return 0
+	}
+	return l.entries[index-l.snapIndex-1].Term
+}
+
+// slice returns up to max entries starting at index
+func (l *metaLog) slice(index uint64, max int) []logEntry {
+	if index <= l.snapIndex || index > l.lastIndex() {
+		return nil
+	}
+	entries := l.entries[index-l.snapIndex-1:]
+	if len(entries) > max {
+		entries = entries[:max]
+	}
+	return append([]logEntry(nil), entries...)
+}
+
+// append writes the entries to the log and syncs them before returning
+func (l *metaLog) append(entries ...logEntry) error {
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if _, err := l.file.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.entries = append(l.entries, entries...)
+	return nil
+}
+
+// truncate drops every entry from index onwards
+func (l *metaLog) truncate(index uint64) error {
+	if index <= l.snapIndex || index > l.lastIndex() {
+		return nil
+	}
+	l.entries = l.entries[:index-l.snapIndex-1]
+	return l.rewrite()
+}
+
+// rewrite replaces the log file with the entries currently held in memory
+func (l *metaLog) rewrite() error {
+	tmpPath := l.logPath() + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	for _, entry := range l.entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		writer.Write(append(data, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(tmpPath, l.logPath()); err != nil {
+		return err
+	}
+
+	l.file.Close()
+	file, err := os.OpenFile(l.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	return nil
+}
+
+// saveSnapshot stores snap and drops the log entries it covers
+func (l *metaLog) saveSnapshot(snap snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(l.snapshotPath(), data); err != nil {
+		return err
+	}
+
+	if snap.Index >= l.lastIndex() || l.termAt(snap.Index) != snap.Term {
+		l.entries = nil
+	} else {
+		l.entries = append([]logEntry(nil), l.entries[snap.Index-l.snapIndex:]...)
+	}
+	l.snapIndex = snap.Index
+	l.snapTerm = snap.Term
+	return l.rewrite()
+}
+
+func (l *metaLog) loadState() (hardState, error) {
+	var state hardState
+	data, err := os.ReadFile(l.statePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+func (l *metaLog) saveState(state hardState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(l.statePath(), data)
+}
+
+// writeFileAtomic writes to a temporary file first so a crash never leaves a half written file
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	return os.Rename(tmpPath, path)
+}
+
+// takeSnapshot captures the tables as they are after the entry at index
+func takeSnapshot(index uint64, term uint64) snapshot {
+	snap := store.Snapshot()
+	snap.Index = index
+	snap.Term = term
+	return snap
+}
+
+// restoreSnapshot replaces the tables with the contents of snap
+func restoreSnapshot(snap snapshot) {
+	store.Restore(snap)
+}
+
+// compactLog periodically folds the applied part of the log into a snapshot
+func compactLog(r *raftNode, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if err := r.compact(); err != nil {
+			fmt.Println("Error compacting metadata log:", err)
+		}
+	}
+}
+
+// snapshotInterval reads SNAPSHOT_INTERVAL (in seconds) from the environment
+func snapshotInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("SNAPSHOT_INTERVAL"))
+	if err != nil || seconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// applyEntry applies a committed entry to the tables. An entry whose preconditions no longer hold
+// changes nothing, on every master alike, and the error goes back to whoever proposed it.
+func applyEntry(entry logEntry) error {
+	switch entry.Op {
+	case opNoop:
+	case opCreateFile:
+		if entry.File != nil {
+			return applyCreateFile(*entry.File)
+		}
+	case opRegisterBlock:
+		if entry.Replica != nil {
+			applyRegisterBlock(*entry.Replica)
+		}
+	case opJoin:
+		if entry.Node != nil {
+			applyJoin(*entry.Node)
+		}
+	case opRemoveBlock:
+		if entry.Replica != nil {
+			applyRemoveBlock(entry.Replica.BlockId, entry.Replica.DataNodeId)
+		}
+	case opDeleteFile:
+		if entry.File != nil {
+			applyDeleteFile(entry.File.FileName)
+		}
+	case opRenameFile:
+		if entry.File != nil {
+			return applyRenameFile(entry.OldName, *entry.File)
+		}
+	case opSetReplication:
+		applySetReplication(entry.Path, entry.Replication)
+	case opDecommission:
+		if entry.Node != nil {
+			applyDecommission(entry.Node.Id, entry.Node.Decommissioning)
+		}
+	case opMkdir:
+		if entry.Dir != nil {
+			return applyMkdir(*entry.Dir)
+		}
+	case opRmdir:
+		if entry.Dir != nil {
+			return applyRmdir(entry.Dir.Path)
+		}
+	default:
+		fmt.Println("Unknown log operation:", entry.Op)
+	}
+	return nil
+}
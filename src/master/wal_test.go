@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func writeLog(t *testing.T, dir string, lines ...string) {
+	t.Helper()
+	l := &metaLog{dir: dir}
+	if err := os.WriteFile(l.logPath(), []byte(strings.Join(lines, "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entryLine(t *testing.T, index uint64, term uint64) string {
+	t.Helper()
+	data, err := json.Marshal(logEntry{Index: index, Term: term, Op: opNoop})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data) + "\n"
+}
+
+func TestLoadEntriesTruncatesTornTail(t *testing.T) {
+	dir := t.TempDir()
+	first, second := entryLine(t, 1, 1), entryLine(t, 2, 1)
+	writeLog(t, dir, first, second, `{"index":3,"te`)
+
+	l, _, err := openMetaLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+	if l.lastIndex() != 2 || l.lastTerm() != 1 {
+		t.Fatalf("last entry %d/%d, want 2/1", l.lastIndex(), l.lastTerm())
+	}
+	data, err := os.ReadFile(l.logPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != first+second {
+		t.Fatalf("log not truncated at the torn entry: %q", data)
+	}
+
+	// New entries go right after the last good one
+	if err := l.append(logEntry{Index: 3, Term: 2, Op: opNoop}); err != nil {
+		t.Fatal(err)
+	}
+	l.file.Close()
+	l, _, err = openMetaLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+	if l.lastIndex() != 3 || l.lastTerm() != 2 {
+		t.Fatalf("last entry %d/%d after reopening, want 3/2", l.lastIndex(), l.lastTerm())
+	}
+}
+
+func TestLoadEntriesFailsOnCorruptMiddle(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, dir, entryLine(t, 1, 1), "garbage\n", entryLine(t, 2, 1))
+	if _, _, err := openMetaLog(dir); err == nil {
+		t.Fatal("opened a log with an unreadable entry in the middle")
+	}
+}
+
+func TestLoadEntriesFailsOnGap(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, dir, entryLine(t, 1, 1), entryLine(t, 3, 1))
+	if _, _, err := openMetaLog(dir); err == nil {
+		t.Fatal("opened a log with a missing entry")
+	}
+}
@@ -0,0 +1,147 @@
+// Package masters lets clients and data keepers talk to a replicated master group
+// without knowing which master is currently the leader.
+package masters
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"src/connpool"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// LeaderKey is the trailer a follower sets to point callers at the current leader
+const LeaderKey = "leader-address"
+
+const maxAttempts = 10
+
+// Addresses returns the master group listed in MASTER_ADDRESSES (comma separated),
+// falling back to the single MASTER_PORT
+func Addresses() []string {
+	addrs := make([]string, 0)
+	for _, addr := range strings.Split(os.Getenv("MASTER_ADDRESSES"), ",") {
+		addr = strings.TrimSpace(addr)
+		if addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		addrs = append(addrs, os.Getenv("MASTER_PORT"))
+	}
+	return addrs
+}
+
+// Conn is a grpc.ClientConnInterface that sends every call to the leader of the
+// master group, following redirects from followers and skipping unreachable masters
+type Conn struct {
+	mu       sync.Mutex
+	addrs    []string
+	leader   string
+	conns    map[string]*grpc.ClientConn
+	releases map[string]func()
+}
+
+// Dial prepares a connection to the master group. Connections to the individual
+// masters are taken lazily from the shared connection pool.
+func Dial(addrs []string) *Conn {
+	return &Conn{addrs: addrs, leader: addrs[0], conns: make(map[string]*grpc.ClientConn), releases: make(map[string]func())}
+}
+
+// Close hands the connections to every master back to the pool
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for addr := range c.conns {
+		c.releases[addr]()
+		delete(c.conns, addr)
+		delete(c.releases, addr)
+	}
+	return nil
+}
+
+func (c *Conn) current() (string, *grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	addr := c.leader
+	if conn, ok := c.conns[addr]; ok {
+		return addr, conn, nil
+	}
+	conn, release, err := connpool.Get(addr)
+	if err != nil {
+		return addr, nil, err
+	}
+	c.conns[addr] = conn
+	c.releases[addr] = release
+	return addr, conn, nil
+}
+
+func (c *Conn) redirect(from string, to string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leader != from {
+		return
+	}
+	if to != "" {
+		c.leader = to
+		return
+	}
+	// No hint, so try the next master in the list
+	for i, addr := range c.addrs {
+		if addr == from {
+			c.leader = c.addrs[(i+1)%len(c.addrs)]
+			return
+		}
+	}
+	c.leader = c.addrs[0]
+}
+
+// Invoke performs a unary call on the current leader
+func (c *Conn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var addr string
+		var conn *grpc.ClientConn
+		addr, conn, err = c.current()
+		if err != nil {
+			c.redirect(addr, "")
+			continue
+		}
+
+		var trailer metadata.MD
+		err = conn.Invoke(ctx, method, args, reply, append(opts, grpc.Trailer(&trailer))...)
+		if err == nil {
+			return nil
+		}
+		if hint := trailer.Get(LeaderKey); len(hint) > 0 && hint[0] != addr {
+			c.redirect(addr, hint[0])
+			continue
+		}
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		// The master is down or has no leader yet
+		c.redirect(addr, "")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt+1) * 50 * time.Millisecond):
+		}
+	}
+	return err
+}
+
+// NewStream opens a stream on the current leader. Streams are not redirected.
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	_, conn, err := c.current()
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, desc, method, opts...)
+}